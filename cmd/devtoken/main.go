@@ -0,0 +1,73 @@
+// devtoken は開発用認証（DEV_AUTH_*）の署名鍵で任意のsubのトークンを発行するコマンド
+//
+//	go run ./cmd/devtoken -sub alice -email alice@example.com -role editor -register
+//
+// -role を指定した場合はトークンのrolesクレームに権限を含める
+// -register を指定した場合はデータベース（DATASOURCE_*）にユーザーを登録する（-role の指定があればusers.roleも更新する）
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go-menu/devauth"
+	"go-menu/di"
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"log"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+func main() {
+	subject := flag.String("sub", "", "トークンのsub（必須）")
+	ttl := flag.Duration("ttl", 24*time.Hour, "トークンの有効期間")
+	email := flag.String("email", "", "emailクレーム")
+	name := flag.String("name", "", "nameクレーム")
+	role := flag.String("role", "", "権限（"+strings.Join(domain.Roles, ", ")+"）")
+	register := flag.Bool("register", false, "データベースにユーザーを登録する")
+	flag.Parse()
+
+	if *subject == "" || (*role != "" && !slices.Contains(domain.Roles, *role)) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	devAuth, err := devauth.Load(devauth.NewConfig())
+	if err != nil {
+		log.Fatal("署名鍵の読み込みに失敗しました: ", err)
+	}
+
+	claims := map[string]interface{}{}
+	if *email != "" {
+		claims["email"] = *email
+	}
+	if *name != "" {
+		claims["name"] = *name
+	}
+	if *role != "" {
+		claims[devauth.RolesClaim] = []string{*role}
+	}
+
+	token, err := devAuth.MintToken(*subject, *ttl, claims)
+	if err != nil {
+		log.Fatal("トークンの発行に失敗しました: ", err)
+	}
+
+	if *register {
+		userDriver := di.InitUserDriver()
+		profile := user.UserProfile{Email: *email, Name: *name}
+		registered, _, err := userDriver.CreateOrGetUser(devauth.IssuerName, *subject, profile)
+		if err != nil {
+			log.Fatal("ユーザーの登録に失敗しました: ", err)
+		}
+		if *role != "" {
+			if _, err := userDriver.UpdateUserRole(registered.UserID, *role); err != nil {
+				log.Fatal("ユーザーの権限の更新に失敗しました: ", err)
+			}
+		}
+	}
+
+	fmt.Println(token)
+}
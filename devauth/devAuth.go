@@ -0,0 +1,232 @@
+package devauth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"go-menu/middleware"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// 開発用の発行者名（users.issuerに保存する）
+	IssuerName = "dev"
+	// 権限（admin, editor, viewer）を含むクレーム名
+	RolesClaim = "roles"
+	// 署名鍵ファイルの既定値
+	defaultKeyFile = ".dev-auth-key.pem"
+	// 発行者のURLの既定値（ディスカバリードキュメントとJWKSはこのURLの下で提供する）
+	defaultIssuer = "http://localhost:8080/v1/dev"
+	// audienceの既定値
+	defaultAudience = "go-menu-dev"
+)
+
+// Config 開発用認証の設定
+type Config struct {
+	// trueの場合のみ開発用認証を有効にする
+	Enabled bool
+	// 署名鍵（PKCS#8形式のEd25519秘密鍵）のファイル。存在しない場合は生成して保存する
+	KeyFile  string
+	Issuer   string
+	Audience string
+}
+
+// NewConfig 環境変数から開発用認証の設定を作成
+// DEV_AUTH_ENABLED: trueの場合のみ有効
+// DEV_AUTH_KEY_FILE・DEV_AUTH_ISSUER・DEV_AUTH_AUDIENCE: 省略時は既定値
+func NewConfig() Config {
+	enabled, _ := strconv.ParseBool(os.Getenv("DEV_AUTH_ENABLED"))
+	config := Config{
+		Enabled:  enabled,
+		KeyFile:  os.Getenv("DEV_AUTH_KEY_FILE"),
+		Issuer:   os.Getenv("DEV_AUTH_ISSUER"),
+		Audience: os.Getenv("DEV_AUTH_AUDIENCE"),
+	}
+	if config.KeyFile == "" {
+		config.KeyFile = defaultKeyFile
+	}
+	if config.Issuer == "" {
+		config.Issuer = defaultIssuer
+	}
+	if config.Audience == "" {
+		config.Audience = defaultAudience
+	}
+
+	return config
+}
+
+// DevAuth ローカルの署名鍵でトークンを発行・検証する開発用の発行者
+type DevAuth struct {
+	config     Config
+	privateKey ed25519.PrivateKey
+	kid        string
+}
+
+// Load 署名鍵を読み込む（ファイルが存在しない場合は生成して保存する）
+func Load(config Config) (*DevAuth, error) {
+	privateKey, err := loadOrGenerateKey(config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return &DevAuth{
+		config:     config,
+		privateKey: privateKey,
+		kid:        thumbprint(publicKey),
+	}, nil
+}
+
+// IssuerConfig トークン検証に登録する発行者の設定
+func (d *DevAuth) IssuerConfig() middleware.IssuerConfig {
+	return middleware.IssuerConfig{
+		Name:       IssuerName,
+		Issuer:     d.config.Issuer,
+		Audience:   d.config.Audience,
+		JWKSURL:    d.JWKSURL(),
+		RolesClaim: RolesClaim,
+	}
+}
+
+// JWKSURL 公開鍵を提供するURL
+func (d *DevAuth) JWKSURL() string {
+	return d.config.Issuer + "/jwks.json"
+}
+
+// Discovery OIDCディスカバリードキュメント
+func (d *DevAuth) Discovery() map[string]interface{} {
+	return map[string]interface{}{
+		"issuer":                                d.config.Issuer,
+		"jwks_uri":                              d.JWKSURL(),
+		"id_token_signing_alg_values_supported": []string{jwt.SigningMethodEdDSA.Alg()},
+	}
+}
+
+// JWKS 公開鍵のJWKS
+func (d *DevAuth) JWKS() middleware.JWKSResponse {
+	publicKey := d.privateKey.Public().(ed25519.PublicKey)
+	return middleware.JWKSResponse{
+		Keys: []middleware.JWK{{
+			Kty: "OKP",
+			Use: "sig",
+			Kid: d.kid,
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(publicKey),
+		}},
+	}
+}
+
+// GetKey kidに対応する公開鍵を取得する（middleware.KeySetの実装）
+func (d *DevAuth) GetKey(kid string) (crypto.PublicKey, error) {
+	if kid != d.kid {
+		return nil, fmt.Errorf("unable to find key for kid %q", kid)
+	}
+
+	return d.privateKey.Public(), nil
+}
+
+// Start 鍵はローカルにあるため何もしない（middleware.KeySetの実装）
+func (d *DevAuth) Start() {}
+
+// Stop 鍵はローカルにあるため何もしない（middleware.KeySetの実装）
+func (d *DevAuth) Stop() {}
+
+// MintToken 指定したsubのトークンを発行する
+// claimsはemail・name・rolesなどの追加のクレーム（標準のクレームは上書きされる）
+func (d *DevAuth) MintToken(subject string, ttl time.Duration, claims map[string]interface{}) (string, error) {
+	if subject == "" {
+		return "", errors.New("subject is required")
+	}
+
+	now := time.Now()
+	mapClaims := jwt.MapClaims{}
+	for key, value := range claims {
+		mapClaims[key] = value
+	}
+	mapClaims["iss"] = d.config.Issuer
+	mapClaims["aud"] = d.config.Audience
+	mapClaims["sub"] = subject
+	mapClaims["iat"] = now.Unix()
+	mapClaims["nbf"] = now.Unix()
+	mapClaims["exp"] = now.Add(ttl).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, mapClaims)
+	token.Header["kid"] = d.kid
+
+	return token.SignedString(d.privateKey)
+}
+
+// loadOrGenerateKey ファイルから秘密鍵を読み込む（存在しない場合は生成して保存する）
+func loadOrGenerateKey(keyFile string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(keyFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return generateKey(keyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data in %s", keyFile)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 private key", keyFile)
+	}
+
+	return privateKey, nil
+}
+
+// generateKey 秘密鍵を生成してファイルに保存する
+func generateKey(keyFile string) (ed25519.PrivateKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	// 既に他のプロセスが作成している場合は上書きしない
+	file, err := os.OpenFile(keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		return loadOrGenerateKey(keyFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	return privateKey, nil
+}
+
+// thumbprint 公開鍵のJWKサムプリント（RFC 7638）をkidとして使う
+func thumbprint(publicKey ed25519.PublicKey) string {
+	canonical := `{"crv":"Ed25519","kty":"OKP","x":"` + base64.RawURLEncoding.EncodeToString(publicKey) + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
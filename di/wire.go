@@ -1,6 +1,7 @@
 package di
 
 import (
+	"go-menu/devauth"
 	"go-menu/gateway"
 	"go-menu/handler"
 	"go-menu/resource"
@@ -15,21 +16,105 @@ func InitSystemHandler() *handler.SystemHandler {
 }
 
 func InitTodoHandler() *handler.MenuHandler {
+	menuUsecase := InitMenuUsecase()
+	menuHandler := handler.ProvideMenuHandler(menuUsecase)
+	return menuHandler
+}
+
+func InitMenuUsecase() usecase.MenuUsecase {
 	db := resource.ConnectToDatabase()
 	menuDriver := menu.ProvideMenuDriver(db)
 	menuPort := gateway.ProvideMenuPort(menuDriver)
-	menuUsecase := usecase.ProvideMenuUsecase(menuPort)
-	menuHandler := handler.ProvideMenuHandler(menuUsecase)
-	return menuHandler
+	genrePort := gateway.ProvideGenrePort(menu.ProvideGenreDriver(db))
+	categoryPort := gateway.ProvideCategoryPort(menu.ProvideCategoryDriver(db))
+	favoritePort := gateway.ProvideFavoritePort(user.ProvideUserDriver(db))
+	profilePort := gateway.ProvideDietaryProfilePort(user.ProvideUserDriver(db))
+	menuUsecase := usecase.ProvideMenuUsecase(menuPort, genrePort, categoryPort, favoritePort, profilePort)
+	return menuUsecase
+}
+
+func InitGenreHandler() *handler.GenreHandler {
+	db := resource.ConnectToDatabase()
+	genreDriver := menu.ProvideGenreDriver(db)
+	genrePort := gateway.ProvideGenrePort(genreDriver)
+	genreUsecase := usecase.ProvideGenreUsecase(genrePort)
+	genreHandler := handler.ProvideGenreHandler(genreUsecase)
+	return genreHandler
+}
+
+func InitCategoryHandler() *handler.CategoryHandler {
+	db := resource.ConnectToDatabase()
+	categoryDriver := menu.ProvideCategoryDriver(db)
+	categoryPort := gateway.ProvideCategoryPort(categoryDriver)
+	categoryUsecase := usecase.ProvideCategoryUsecase(categoryPort)
+	categoryHandler := handler.ProvideCategoryHandler(categoryUsecase)
+	return categoryHandler
+}
+
+func InitShoppingListHandler() *handler.ShoppingListHandler {
+	db := resource.ConnectToDatabase()
+	menuPort := gateway.ProvideMenuPort(menu.ProvideMenuDriver(db))
+	shoppingListUsecase := usecase.ProvideShoppingListUsecase(menuPort)
+	shoppingListHandler := handler.ProvideShoppingListHandler(shoppingListUsecase)
+	return shoppingListHandler
 }
 
 func InitFavoriteHandler() *handler.FavoriteHandler {
 	db := resource.ConnectToDatabase()
 	userDriver := user.ProvideUserDriver(db)
-	favoriteHandler := handler.ProvideFavoriteHandler(userDriver)
+	favoriteUsecase := usecase.ProvideFavoriteUsecase(gateway.ProvideFavoritePort(userDriver))
+	favoriteHandler := handler.ProvideFavoriteHandler(userDriver, favoriteUsecase)
 	return favoriteHandler
 }
 
+func InitHistoryHandler() *handler.HistoryHandler {
+	db := resource.ConnectToDatabase()
+	userDriver := user.ProvideUserDriver(db)
+	historyHandler := handler.ProvideHistoryHandler(userDriver)
+	return historyHandler
+}
+
+func InitMealPlanHandler() *handler.MealPlanHandler {
+	db := resource.ConnectToDatabase()
+	userDriver := user.ProvideUserDriver(db)
+	menuPort := gateway.ProvideMenuPort(menu.ProvideMenuDriver(db))
+	mealPlanPort := gateway.ProvideMealPlanPort(userDriver)
+	favoritePort := gateway.ProvideFavoritePort(userDriver)
+	profilePort := gateway.ProvideDietaryProfilePort(userDriver)
+	mealPlanUsecase := usecase.ProvideMealPlanUsecase(menuPort, mealPlanPort, favoritePort, profilePort)
+	mealPlanHandler := handler.ProvideMealPlanHandler(mealPlanUsecase)
+	return mealPlanHandler
+}
+
+func InitCalendarHandler() *handler.CalendarHandler {
+	db := resource.ConnectToDatabase()
+	userDriver := user.ProvideUserDriver(db)
+	mealPlanPort := gateway.ProvideMealPlanPort(userDriver)
+	tokenPort := gateway.ProvideCalendarTokenPort(userDriver)
+	calendarUsecase := usecase.ProvideCalendarUsecase(mealPlanPort, tokenPort)
+	calendarHandler := handler.ProvideCalendarHandler(calendarUsecase, handler.NewCalendarConfig())
+	return calendarHandler
+}
+
+func InitSuggestionHandler() *handler.SuggestionHandler {
+	db := resource.ConnectToDatabase()
+	menuPort := gateway.ProvideMenuPort(menu.ProvideMenuDriver(db))
+	userDriver := user.ProvideUserDriver(db)
+	suggestionPort := gateway.ProvideSuggestionPort(userDriver)
+	profilePort := gateway.ProvideDietaryProfilePort(userDriver)
+	suggestionUsecase := usecase.ProvideSuggestionUsecase(menuPort, suggestionPort, profilePort)
+	suggestionHandler := handler.ProvideSuggestionHandler(suggestionUsecase)
+	return suggestionHandler
+}
+
+func InitProfileHandler() *handler.ProfileHandler {
+	db := resource.ConnectToDatabase()
+	profilePort := gateway.ProvideDietaryProfilePort(user.ProvideUserDriver(db))
+	profileUsecase := usecase.ProvideDietaryProfileUsecase(profilePort)
+	profileHandler := handler.ProvideProfileHandler(profileUsecase)
+	return profileHandler
+}
+
 func InitUserDriver() user.UserDriver {
 	db := resource.ConnectToDatabase()
 	userDriver := user.ProvideUserDriver(db)
@@ -42,3 +127,8 @@ func InitUserHandler() *handler.UserHandler {
 	userHandler := handler.ProvideUserHandler(userDriver)
 	return userHandler
 }
+
+func InitDevAuthHandler(devAuth *devauth.DevAuth) *handler.DevAuthHandler {
+	devAuthHandler := handler.ProvideDevAuthHandler(devAuth)
+	return devAuthHandler
+}
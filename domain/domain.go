@@ -1,15 +1,366 @@
 package domain
 
+import "time"
+
 // レスポンス用のメニュー情報
 type Menu struct {
 	MenuId      uint   `json:"menu_id"`
 	MenuName    string `json:"menu_name"`
-	GenreIds    []uint `json:"genre_ids"`
-	CategoryIds []uint `json:"category_ids"`
+	Description string `json:"description"`
+	// 価格（円）
+	Price    *uint `json:"price"`
+	Calories *uint `json:"calories"`
+	// 調理時間（分）
+	CookingMinutes *uint  `json:"cooking_minutes"`
+	ImageUrl       string `json:"image_url"`
+	GenreIds       []uint `json:"genre_ids"`
+	CategoryIds    []uint `json:"category_ids"`
+	// 含まれるアレルゲンと対応している食事制限
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+	// expand指定時のみレスポンスに含める
+	Genres     []Genre    `json:"genres,omitempty"`
+	Categories []Category `json:"categories,omitempty"`
+	// 論理削除した日時（削除されていない場合は省略）
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// メニュー一覧の並び順
+type MenuSort string
+
+const (
+	MenuSortIdAsc    MenuSort = "menu_id"
+	MenuSortIdDesc   MenuSort = "-menu_id"
+	MenuSortNameAsc  MenuSort = "menu_name"
+	MenuSortNameDesc MenuSort = "-menu_name"
+)
+
+// 複数指定したジャンル・カテゴリの一致条件
+type MatchMode string
+
+const (
+	// いずれかに一致
+	MatchAny MatchMode = "any"
+	// すべてに一致
+	MatchAll MatchMode = "all"
+)
+
+// メニュー一覧の検索条件
+type MenuQuery struct {
+	Limit int
+	// 指定した位置より後ろのメニューを取得する（キーセットページネーション）
+	After         *MenuCursor
+	Sort          MenuSort
+	GenreIds      []uint
+	GenreMatch    MatchMode
+	CategoryIds   []uint
+	CategoryMatch MatchMode
+	// メニュー名の部分一致
+	Keyword string
+	// 指定した値以下のメニューに絞り込む（nilの場合は絞り込まない）
+	MaxPrice    *uint
+	MaxCalories *uint
+	MaxMinutes  *uint
+	// ログインユーザー（0以外の場合は食事制限プロファイルに反するメニューを除外する）
+	UserId uint
+	// 指定したアレルゲンを含むメニューを除外する
+	ExcludeAllergens []string
+	// 指定した食事制限すべてに対応するメニューに絞り込む
+	RequireDiets []string
+	// trueの場合は論理削除したメニューも含める（管理者のみ）
+	IncludeDeleted bool
+}
+
+// キーセットページネーションの位置
+type MenuCursor struct {
+	MenuId   uint   `json:"menu_id"`
+	MenuName string `json:"menu_name,omitempty"`
+}
+
+// メニュー一覧の検索結果
+type MenuPage struct {
+	Menus []Menu
+	// 次のページがない場合はnil
+	Next  *MenuCursor
+	Total int64
+}
+
+// ランダムにメニューを選ぶ条件
+type RandomMenuQuery struct {
+	// いずれかのジャンル・カテゴリを持つメニューに絞り込む
+	GenreIds    []uint
+	CategoryIds []uint
+	// 候補から除外するメニュー
+	ExcludeIds []uint
+	// 選ぶ件数（重複なし）
+	Count int
+	// 同じシードと条件であれば同じ結果になる
+	Seed int64
+	// ログインユーザー（0以外の場合は食事制限プロファイルとお気に入りの重み付けを適用する）
+	UserId uint
+	// お気に入りメニューの選ばれやすさ（1で重み付けなし）
+	FavoriteWeight float64
+	// 指定したアレルゲンを含むメニューを除外する
+	ExcludeAllergens []string
+	// 指定した食事制限すべてに対応するメニューに絞り込む
+	RequireDiets []string
+}
+
+// ランダムに選ぶ候補メニューの条件
+type MenuCandidateQuery struct {
+	// いずれかのジャンル・カテゴリを持つメニューに絞り込む
+	GenreIds    []uint
+	CategoryIds []uint
+	// 候補から除外するメニュー
+	ExcludeIds []uint
+	// 指定したアレルゲンを含むメニューを除外する
+	ExcludeAllergens []string
+	// 指定した食事制限すべてに対応するメニューに絞り込む
+	RequireDiets []string
+	// 上限件数に関わらず優先して候補に含めるメニュー（お気に入りなど）
+	PreferIds []uint
+	// 候補の最大件数（超える場合はSeedで決まる順に絞り込む）
+	Limit int
+	Seed  int64
+}
+
+// ジャンル情報
+type Genre struct {
+	GenreId   uint   `json:"genre_id"`
+	GenreName string `json:"genre_name"`
+}
+
+// カテゴリ情報
+type Category struct {
+	CategoryId   uint   `json:"category_id"`
+	CategoryName string `json:"category_name"`
 }
 
 // お気に入り情報
 type Favorites struct {
-	FavoriteID uint `json:"favorite_id"`
-	MenuID     uint `json:"menu_id"`
+	FavoriteID uint      `json:"favorite_id"`
+	MenuID     uint      `json:"menu_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	// メニューが削除されている場合はtrue
+	MenuDeleted bool `json:"menu_deleted"`
+	// お気に入りのメニュー（ジャンル名・カテゴリ名を含む、物理削除されている場合はnil）
+	Menu *Menu `json:"menu"`
+}
+
+// お気に入り一覧の並び順
+type FavoriteSort string
+
+const (
+	FavoriteSortCreatedAsc  FavoriteSort = "created_at"
+	FavoriteSortCreatedDesc FavoriteSort = "-created_at"
+)
+
+// お気に入り一覧の検索条件
+type FavoriteQuery struct {
+	UserId uint
+	Limit  int
+	// 指定した位置より後ろのお気に入りを取得する（キーセットページネーション）
+	After *FavoriteCursor
+	Sort  FavoriteSort
+}
+
+// お気に入り一覧のキーセットページネーションの位置
+type FavoriteCursor struct {
+	FavoriteId uint      `json:"favorite_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// お気に入り一覧の検索結果
+type FavoritePage struct {
+	Favorites []Favorites
+	// 次のページがない場合はnil
+	Next  *FavoriteCursor
+	Total int64
+}
+
+// ユーザーの権限
+const (
+	// メニューの復元など、すべての操作ができる
+	RoleAdmin = "admin"
+	// メニュー・ジャンル・カテゴリを編集できる
+	RoleEditor = "editor"
+	// 参照と自分のデータの操作のみできる
+	RoleViewer = "viewer"
+)
+
+// 権限の一覧（強い順）
+var Roles = []string{RoleAdmin, RoleEditor, RoleViewer}
+
+// 食事の時間帯
+const (
+	MealSlotBreakfast = "breakfast"
+	MealSlotLunch     = "lunch"
+	MealSlotDinner    = "dinner"
+)
+
+// 食事の時間帯（1日の中の順）
+var MealSlots = []string{MealSlotBreakfast, MealSlotLunch, MealSlotDinner}
+
+// 日付の形式（YYYY-MM-DD）
+const DateLayout = "2006-01-02"
+
+// 食事履歴
+type MealHistory struct {
+	HistoryID uint   `json:"history_id"`
+	MenuID    uint   `json:"menu_id"`
+	EatenOn   string `json:"eaten_on"`
+	MealSlot  string `json:"meal_slot"`
+}
+
+// 献立
+type MealPlan struct {
+	PlanID    uint   `json:"plan_id"`
+	UserID    uint   `json:"-"`
+	MenuID    uint   `json:"menu_id"`
+	PlannedOn string `json:"planned_on"`
+	MealSlot  string `json:"meal_slot"`
+	// メニューが削除されている場合は空
+	MenuName string `json:"menu_name"`
+	// メニューが削除されている場合はfalse
+	MenuExists bool `json:"menu_exists"`
+}
+
+// 献立に追加する内容
+type MealPlanEntry struct {
+	PlannedOn time.Time
+	MealSlot  string
+	MenuID    uint
+}
+
+// メニューの提案
+type Suggestion struct {
+	SuggestionID uint       `json:"suggestion_id"`
+	UserID       uint       `json:"-"`
+	MenuID       uint       `json:"menu_id"`
+	SuggestedAt  time.Time  `json:"suggested_at"`
+	AcceptedAt   *time.Time `json:"accepted_at"`
+	// 提案したメニューの詳細
+	Menu *Menu `json:"menu,omitempty"`
+}
+
+// アレルゲン（特定原材料7品目）
+const (
+	AllergenWheat     = "wheat"
+	AllergenEgg       = "egg"
+	AllergenMilk      = "milk"
+	AllergenShrimp    = "shrimp"
+	AllergenCrab      = "crab"
+	AllergenBuckwheat = "buckwheat"
+	AllergenPeanut    = "peanut"
+)
+
+// Allergens は登録できるアレルゲンの一覧
+var Allergens = []string{AllergenWheat, AllergenEgg, AllergenMilk, AllergenShrimp, AllergenCrab, AllergenBuckwheat, AllergenPeanut}
+
+// 食事制限
+const (
+	DietVegetarian = "vegetarian"
+	DietHalal      = "halal"
+)
+
+// Diets は登録できる食事制限の一覧
+var Diets = []string{DietVegetarian, DietHalal}
+
+// 食事制限プロファイル
+type DietaryProfile struct {
+	// 含まれるメニューを表示しないアレルゲン
+	Allergens []string `json:"allergens"`
+	// 対応しているメニューのみを表示する食事制限
+	Diets []string `json:"diets"`
+}
+
+// 売り場
+const (
+	SectionProduce = "produce"
+	SectionMeat    = "meat"
+	SectionSeafood = "seafood"
+	SectionDairy   = "dairy"
+	SectionBakery  = "bakery"
+	SectionPantry  = "pantry"
+	SectionFrozen  = "frozen"
+	SectionOther   = "other"
+)
+
+// 指定可能な売り場（買い物リストはこの順に並べる）
+var StoreSections = []string{SectionProduce, SectionMeat, SectionSeafood, SectionDairy, SectionBakery, SectionPantry, SectionFrozen, SectionOther}
+
+// 材料
+type Ingredient struct {
+	Name string `json:"name"`
+	// 1人前の分量
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	// 売り場
+	Section string `json:"section"`
+}
+
+// 調理手順
+type RecipeStep struct {
+	// 1始まりの手順の番号
+	StepNumber  int    `json:"step_number"`
+	Instruction string `json:"instruction"`
+}
+
+// レシピ（材料と調理手順）
+type Recipe struct {
+	MenuId      uint         `json:"menu_id"`
+	Ingredients []Ingredient `json:"ingredients"`
+	Steps       []RecipeStep `json:"steps"`
+}
+
+// 買い物リストの対象メニューと人数
+type ShoppingListItem struct {
+	MenuId   uint
+	Servings uint
+}
+
+// 買い物リストの材料（同じ名前・単位の分量を合算したもの）
+type ShoppingListEntry struct {
+	Name     string  `json:"name"`
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+}
+
+// 売り場ごとの買い物リスト
+type ShoppingListSection struct {
+	Section string              `json:"section"`
+	Items   []ShoppingListEntry `json:"items"`
+}
+
+// 買い物リスト
+type ShoppingList struct {
+	Sections []ShoppingListSection `json:"sections"`
+}
+
+// 一括登録するメニューの1行
+type MenuImportRow struct {
+	// 1始まりの行番号（CSVはヘッダー行を含むファイル上の行、JSONは配列の要素番号）
+	Row            int
+	MenuName       string
+	Description    string
+	Price          *uint
+	Calories       *uint
+	CookingMinutes *uint
+	ImageUrl       string
+	// ジャンル名・カテゴリ名（登録済みのものを名前で指定する）
+	GenreNames    []string
+	CategoryNames []string
+	// 読み込み時に見つかった問題（数値の形式が不正な場合など）
+	Errors []FieldError
+}
+
+// メニューの一括登録結果
+type MenuImportResult struct {
+	DryRun bool `json:"dry_run"`
+	// 読み込んだ行数
+	Total int `json:"total"`
+	// 行ごとの検証エラー（dry_runの場合のみ）
+	Errors []FieldError `json:"errors"`
+	// 登録したメニュー（dry_runでない場合のみ）
+	Menus []Menu `json:"menus,omitempty"`
 }
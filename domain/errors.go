@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrorKind はドメインエラーの種類
+type ErrorKind int
+
+const (
+	// KindInternal は分類されていない内部エラー
+	KindInternal ErrorKind = iota
+	// KindBadRequest はリクエストの形式が不正であることを表す
+	KindBadRequest
+	// KindValidation は入力値の検証に失敗したことを表す
+	KindValidation
+	// KindNotFound は対象のリソースが存在しないことを表す
+	KindNotFound
+	// KindConflict はリソースの状態と競合することを表す
+	KindConflict
+	// KindForbidden は操作の権限がないことを表す
+	KindForbidden
+	// KindUnauthorized は認証されていないことを表す
+	KindUnauthorized
+)
+
+// Error はドメイン層で扱う型付きエラー
+// ドライバーやゲートウェイで発生したエラーはこの型に包んで返す
+type Error struct {
+	Kind ErrorKind
+	// クライアントがエラーを判別するための安定したコード（例: menu_not_found）
+	Code    string
+	Message string
+	// 検証エラーの詳細（KindValidationの場合のみ）
+	Fields []FieldError
+	// 原因となったエラー
+	Err error
+}
+
+func (e *Error) Error() string {
+	message := e.Message
+	if len(e.Fields) > 0 {
+		fields := make([]string, 0, len(e.Fields))
+		for _, field := range e.Fields {
+			fields = append(fields, field.Field+": "+field.Message)
+		}
+		message += ": " + strings.Join(fields, ", ")
+	}
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap は原因となったエラーを設定する
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// FieldError は入力項目ごとの検証エラー
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	// 問題のあったID（重複や存在しないIDの場合のみ）
+	Values []uint `json:"values,omitempty"`
+	// 問題のあった行（一括登録の場合のみ、1始まり）
+	Row int `json:"row,omitempty"`
+}
+
+// NewBadRequest はリクエスト形式の不正を表すエラーを作成する
+func NewBadRequest(code, message string) *Error {
+	return &Error{Kind: KindBadRequest, Code: code, Message: message}
+}
+
+// NewValidation は入力値の検証エラーを作成する
+func NewValidation(fields []FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: "validation_failed", Message: "validation failed", Fields: fields}
+}
+
+// NewNotFound はリソースが存在しないことを表すエラーを作成する
+func NewNotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// NewConflict はリソースの状態との競合を表すエラーを作成する
+func NewConflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// NewForbidden は権限がないことを表すエラーを作成する
+func NewForbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// NewUnauthorized は認証されていないことを表すエラーを作成する
+func NewUnauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// KindOf はエラーの種類を返す
+// ドメインエラーでない場合はKindInternalを返す
+func KindOf(err error) ErrorKind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+
+	return KindInternal
+}
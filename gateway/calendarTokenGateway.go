@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"go-menu/resource/user"
+	"go-menu/usecase/port"
+)
+
+type CalendarTokenGateway struct {
+	userDriver user.UserDriver
+}
+
+func ProvideCalendarTokenPort(d user.UserDriver) port.CalendarTokenPort {
+	return &CalendarTokenGateway{d}
+}
+
+// SaveCalendarToken はユーザーのカレンダートークンのハッシュを保存する
+func (t CalendarTokenGateway) SaveCalendarToken(userId uint, tokenHash string) error {
+	_, err := t.userDriver.SaveCalendarToken(userId, tokenHash)
+	return err
+}
+
+// DeleteCalendarToken はユーザーのカレンダートークンを削除する
+func (t CalendarTokenGateway) DeleteCalendarToken(userId uint) error {
+	return t.userDriver.DeleteCalendarToken(userId)
+}
+
+// FindUserIdByCalendarToken はトークンのハッシュからユーザーIDを取得する
+func (t CalendarTokenGateway) FindUserIdByCalendarToken(tokenHash string) (uint, error) {
+	token, err := t.userDriver.GetCalendarTokenByHash(tokenHash)
+	if err != nil {
+		return 0, err
+	}
+
+	return token.UserID, nil
+}
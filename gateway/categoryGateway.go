@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"errors"
+	"go-menu/domain"
+	"go-menu/resource/menu"
+	"go-menu/usecase/port"
+
+	"gorm.io/gorm"
+)
+
+type CategoryGateway struct {
+	categoryDriver menu.CategoryDriver
+}
+
+func ProvideCategoryPort(d menu.CategoryDriver) port.CategoryPort {
+	return &CategoryGateway{d}
+}
+
+func (t CategoryGateway) GetAll() ([]domain.Category, error) {
+	results, err := t.categoryDriver.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []domain.Category{}
+	for _, result := range results {
+		categories = append(categories, t.toDomain(result))
+	}
+
+	return categories, nil
+}
+
+// GetByIds は指定したIDのカテゴリを取得する
+func (t CategoryGateway) GetByIds(categoryIds []uint) ([]domain.Category, error) {
+	results, err := t.categoryDriver.GetByIds(categoryIds)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []domain.Category{}
+	for _, result := range results {
+		categories = append(categories, t.toDomain(result))
+	}
+
+	return categories, nil
+}
+
+// CreateCategory はカテゴリを作成する
+func (t CategoryGateway) CreateCategory(category domain.Category) (domain.Category, error) {
+	result, err := t.categoryDriver.CreateCategory(category.CategoryName)
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// UpdateCategory はカテゴリを更新する
+func (t CategoryGateway) UpdateCategory(category domain.Category) (domain.Category, error) {
+	result, err := t.categoryDriver.UpdateCategory(category.CategoryId, category.CategoryName)
+	if err != nil {
+		return domain.Category{}, t.convertError(err)
+	}
+
+	return t.toDomain(result), nil
+}
+
+// DeleteCategory はカテゴリを削除する
+func (t CategoryGateway) DeleteCategory(categoryId uint, cascade bool) error {
+	err := t.categoryDriver.DeleteCategory(categoryId, cascade)
+	if err != nil {
+		return t.convertError(err)
+	}
+
+	return nil
+}
+
+// toDomain はカテゴリのモデルをドメインモデルに変換する
+func (t CategoryGateway) toDomain(category menu.Category) domain.Category {
+	return domain.Category{
+		CategoryId:   category.CategoryId,
+		CategoryName: category.CategoryName,
+	}
+}
+
+// convertError はドライバーのエラーをドメインのエラーに変換する
+func (t CategoryGateway) convertError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.NewNotFound("category_not_found", "category not found").Wrap(err)
+	case errors.Is(err, menu.ErrReferenced):
+		return domain.NewConflict("category_in_use", "category is referenced by menus").Wrap(err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,43 @@
+package gateway
+
+import (
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"go-menu/usecase/port"
+)
+
+type DietaryProfileGateway struct {
+	userDriver user.UserDriver
+}
+
+func ProvideDietaryProfilePort(d user.UserDriver) port.DietaryProfilePort {
+	return &DietaryProfileGateway{d}
+}
+
+// GetDietaryProfile はユーザーの食事制限プロファイルを取得する
+func (t DietaryProfileGateway) GetDietaryProfile(userId uint) (domain.DietaryProfile, error) {
+	result, err := t.userDriver.GetDietaryProfile(userId)
+	if err != nil {
+		return domain.DietaryProfile{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// UpdateDietaryProfile はユーザーの食事制限プロファイルを置き換える
+func (t DietaryProfileGateway) UpdateDietaryProfile(userId uint, profile domain.DietaryProfile) (domain.DietaryProfile, error) {
+	result, err := t.userDriver.UpdateDietaryProfile(userId, profile.Allergens, profile.Diets)
+	if err != nil {
+		return domain.DietaryProfile{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// toDomain は食事制限プロファイルのモデルをドメインモデルに変換する
+func (t DietaryProfileGateway) toDomain(profile user.DietaryProfile) domain.DietaryProfile {
+	return domain.DietaryProfile{
+		Allergens: profile.Allergens,
+		Diets:     profile.Diets,
+	}
+}
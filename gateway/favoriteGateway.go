@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"go-menu/usecase/port"
+)
+
+type FavoriteGateway struct {
+	userDriver user.UserDriver
+}
+
+func ProvideFavoritePort(d user.UserDriver) port.FavoritePort {
+	return &FavoriteGateway{d}
+}
+
+// GetFavoriteMenuIds はユーザーのお気に入りメニューのIDリストを取得する
+func (t FavoriteGateway) GetFavoriteMenuIds(userId uint) ([]uint, error) {
+	favorites, err := t.userDriver.GetUserFavorites(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	menuIds := []uint{}
+	for _, favorite := range favorites {
+		menuIds = append(menuIds, favorite.MenuID)
+	}
+
+	return menuIds, nil
+}
+
+// FindFavorites はユーザーのお気に入りをメニューとともに取得する
+func (t FavoriteGateway) FindFavorites(query domain.FavoriteQuery) (domain.FavoritePage, error) {
+	driverQuery := user.FavoriteQuery{
+		UserID: query.UserId,
+		Limit:  query.Limit,
+		Desc:   query.Sort == domain.FavoriteSortCreatedDesc,
+	}
+	if query.After != nil {
+		driverQuery.HasAfter = true
+		driverQuery.AfterCreatedAt = query.After.CreatedAt
+		driverQuery.AfterID = query.After.FavoriteId
+	}
+
+	results, hasNext, total, err := t.userDriver.FindFavorites(driverQuery)
+	if err != nil {
+		return domain.FavoritePage{}, err
+	}
+
+	page := domain.FavoritePage{
+		Favorites: []domain.Favorites{},
+		Total:     total,
+	}
+	for _, result := range results {
+		page.Favorites = append(page.Favorites, t.toDomain(result))
+	}
+	if hasNext {
+		last := results[len(results)-1]
+		page.Next = &domain.FavoriteCursor{FavoriteId: last.FavoriteID, CreatedAt: last.CreatedAt}
+	}
+
+	return page, nil
+}
+
+// toDomain はお気に入りのモデルをドメインモデルに変換する
+func (t FavoriteGateway) toDomain(favorite user.Favorite) domain.Favorites {
+	result := domain.Favorites{
+		FavoriteID: favorite.FavoriteID,
+		MenuID:     favorite.MenuID,
+		CreatedAt:  favorite.CreatedAt,
+		// 結合したメニューがない場合は物理削除されている
+		MenuDeleted: favorite.Menu.MenuId == 0 || favorite.Menu.DeletedAt.Valid,
+	}
+	if favorite.Menu.MenuId != 0 {
+		menu := MenuGateway{}.toDomain(favorite.Menu)
+		result.Menu = &menu
+	}
+
+	return result
+}
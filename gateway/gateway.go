@@ -1,11 +1,18 @@
 package gateway
 
 import (
+	"errors"
 	"go-menu/domain"
 	"go-menu/resource/menu"
 	"go-menu/usecase/port"
+	"time"
+
+	"gorm.io/gorm"
 )
 
+// エクスポート時に一度に取得するメニューの件数
+const exportBatchSize = 500
+
 type MenuGateway struct {
 	menuDriver menu.MenuDriver
 }
@@ -22,13 +29,117 @@ func (t MenuGateway) GetAll() ([]domain.Menu, error) {
 
 	var menus []domain.Menu
 	for _, result := range results {
-		todo := domain.Menu{
-			MenuId:      result.MenuId,
-			MenuName:    result.MenuName,
-			GenreIds:    t.getRestGenreIds(result.Genres),
-			CategoryIds: t.getRestCategoryIds(result.Categories),
+		menus = append(menus, t.toDomain(result))
+	}
+
+	return menus, nil
+}
+
+// EachMenuBatch はすべてのメニューをmenu_id順に一定件数ずつ取得し、fnに渡す
+func (t MenuGateway) EachMenuBatch(fn func(menus []domain.Menu) error) error {
+	return t.menuDriver.EachMenuBatch(exportBatchSize, func(results []menu.Menu) error {
+		menus := []domain.Menu{}
+		for _, result := range results {
+			menus = append(menus, t.toDomain(result))
 		}
-		menus = append(menus, todo)
+
+		return fn(menus)
+	})
+}
+
+// FindMenus は条件に一致するメニューを1ページ分取得する
+func (t MenuGateway) FindMenus(query domain.MenuQuery) (domain.MenuPage, error) {
+	driverQuery := menu.MenuQuery{
+		Limit:       query.Limit,
+		SortByName:  query.Sort == domain.MenuSortNameAsc || query.Sort == domain.MenuSortNameDesc,
+		Desc:        query.Sort == domain.MenuSortIdDesc || query.Sort == domain.MenuSortNameDesc,
+		GenreIds:    query.GenreIds,
+		GenreAll:    query.GenreMatch == domain.MatchAll,
+		CategoryIds: query.CategoryIds,
+		CategoryAll: query.CategoryMatch == domain.MatchAll,
+		Keyword:     query.Keyword,
+		MaxPrice:    query.MaxPrice,
+		MaxCalories: query.MaxCalories,
+		MaxMinutes:  query.MaxMinutes,
+
+		ExcludeAllergens: query.ExcludeAllergens,
+		RequireDiets:     query.RequireDiets,
+		IncludeDeleted:   query.IncludeDeleted,
+	}
+	if query.After != nil {
+		driverQuery.HasAfter = true
+		driverQuery.AfterId = query.After.MenuId
+		driverQuery.AfterName = query.After.MenuName
+	}
+
+	results, hasNext, total, err := t.menuDriver.FindMenus(driverQuery)
+	if err != nil {
+		return domain.MenuPage{}, err
+	}
+
+	page := domain.MenuPage{
+		Menus: []domain.Menu{},
+		Total: total,
+	}
+	for _, result := range results {
+		page.Menus = append(page.Menus, t.toDomain(result))
+	}
+	if hasNext {
+		last := results[len(results)-1]
+		page.Next = &domain.MenuCursor{MenuId: last.MenuId}
+		if driverQuery.SortByName {
+			page.Next.MenuName = last.MenuName
+		}
+	}
+
+	return page, nil
+}
+
+// FindCandidates はランダムに選ぶ候補のメニューを取得する
+func (t MenuGateway) FindCandidates(query domain.MenuCandidateQuery) ([]domain.Menu, error) {
+	results, err := t.menuDriver.FindCandidates(menu.CandidateQuery{
+		GenreIds:         query.GenreIds,
+		CategoryIds:      query.CategoryIds,
+		ExcludeIds:       query.ExcludeIds,
+		ExcludeAllergens: query.ExcludeAllergens,
+		RequireDiets:     query.RequireDiets,
+		PreferIds:        query.PreferIds,
+		Limit:            query.Limit,
+		Seed:             query.Seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	menus := []domain.Menu{}
+	for _, result := range results {
+		menus = append(menus, t.toDomain(result))
+	}
+
+	return menus, nil
+}
+
+// GetMenu はメニューを1件取得する
+func (t MenuGateway) GetMenu(menuId uint) (domain.Menu, error) {
+	result, err := t.menuDriver.GetMenu(menuId)
+
+	if err != nil {
+		return domain.Menu{}, t.convertError(err)
+	}
+
+	return t.toDomain(result), nil
+}
+
+// GetMenusByIds は指定したIDのメニューを取得する
+func (t MenuGateway) GetMenusByIds(menuIds []uint) ([]domain.Menu, error) {
+	results, err := t.menuDriver.GetMenusByIds(menuIds)
+	if err != nil {
+		return nil, err
+	}
+
+	menus := []domain.Menu{}
+	for _, result := range results {
+		menus = append(menus, t.toDomain(result))
 	}
 
 	return menus, nil
@@ -36,36 +147,52 @@ func (t MenuGateway) GetAll() ([]domain.Menu, error) {
 
 // CreateMenu はメニューを作成する
 func (t MenuGateway) CreateMenu(menu domain.Menu) (domain.Menu, error) {
-	result, err := t.menuDriver.CreateMenu(menu.MenuName, menu.GenreIds, menu.CategoryIds)
+	result, err := t.menuDriver.CreateMenu(menu.MenuName, t.toAttributes(menu), menu.GenreIds, menu.CategoryIds)
 
 	if err != nil {
 		return domain.Menu{}, err
 	}
 
-	menu = domain.Menu{
-		MenuId:      result.MenuId,
-		MenuName:    result.MenuName,
-		GenreIds:    t.getRestGenreIds(result.Genres),
-		CategoryIds: t.getRestCategoryIds(result.Categories),
-	}
+	menu = t.toDomain(result)
 
 	return menu, nil
 }
 
+// CreateMenus は複数のメニューをまとめて作成する
+func (t MenuGateway) CreateMenus(menus []domain.Menu) ([]domain.Menu, error) {
+	newMenus := []menu.NewMenu{}
+	for _, m := range menus {
+		newMenus = append(newMenus, menu.NewMenu{
+			MenuName:       m.MenuName,
+			MenuAttributes: t.toAttributes(m),
+			GenreIds:       m.GenreIds,
+			CategoryIds:    m.CategoryIds,
+		})
+	}
+
+	results, err := t.menuDriver.CreateMenus(newMenus)
+
+	if err != nil {
+		return nil, err
+	}
+
+	created := []domain.Menu{}
+	for _, result := range results {
+		created = append(created, t.toDomain(result))
+	}
+
+	return created, nil
+}
+
 // UpdateMenu はメニューを更新する
 func (t MenuGateway) UpdateMenu(menu domain.Menu) (domain.Menu, error) {
-	result, err := t.menuDriver.UpdateMenu(menu.MenuId, menu.MenuName, menu.GenreIds, menu.CategoryIds)
+	result, err := t.menuDriver.UpdateMenu(menu.MenuId, menu.MenuName, t.toAttributes(menu), menu.GenreIds, menu.CategoryIds)
 
 	if err != nil {
-		return domain.Menu{}, err
+		return domain.Menu{}, t.convertError(err)
 	}
 
-	menu = domain.Menu{
-		MenuId:      result.MenuId,
-		MenuName:    result.MenuName,
-		GenreIds:    t.getRestGenreIds(result.Genres),
-		CategoryIds: t.getRestCategoryIds(result.Categories),
-	}
+	menu = t.toDomain(result)
 
 	return menu, nil
 }
@@ -75,15 +202,10 @@ func (t MenuGateway) UpdateGenreRelations(menuId uint, genreIds []uint) (domain.
 	result, err := t.menuDriver.UpdateGenreRelations(menuId, genreIds)
 
 	if err != nil {
-		return domain.Menu{}, err
+		return domain.Menu{}, t.convertError(err)
 	}
 
-	menu := domain.Menu{
-		MenuId:      result.MenuId,
-		MenuName:    result.MenuName,
-		GenreIds:    t.getRestGenreIds(result.Genres),
-		CategoryIds: t.getRestCategoryIds(result.Categories),
-	}
+	menu := t.toDomain(result)
 
 	return menu, nil
 }
@@ -93,30 +215,171 @@ func (t MenuGateway) UpdateCategoryRelations(menuId uint, categoryIds []uint) (d
 	result, err := t.menuDriver.UpdateCategoryRelations(menuId, categoryIds)
 
 	if err != nil {
-		return domain.Menu{}, err
+		return domain.Menu{}, t.convertError(err)
 	}
 
-	menu := domain.Menu{
-		MenuId:      result.MenuId,
-		MenuName:    result.MenuName,
-		GenreIds:    t.getRestGenreIds(result.Genres),
-		CategoryIds: t.getRestCategoryIds(result.Categories),
-	}
+	menu := t.toDomain(result)
 
 	return menu, nil
 }
 
+// UpdateAllergens はメニューに含まれるアレルゲンを更新する
+func (t MenuGateway) UpdateAllergens(menuId uint, allergens []string) (domain.Menu, error) {
+	result, err := t.menuDriver.UpdateAllergens(menuId, allergens)
+
+	if err != nil {
+		return domain.Menu{}, t.convertError(err)
+	}
+
+	return t.toDomain(result), nil
+}
+
+// UpdateDiets はメニューが対応する食事制限を更新する
+func (t MenuGateway) UpdateDiets(menuId uint, diets []string) (domain.Menu, error) {
+	result, err := t.menuDriver.UpdateDiets(menuId, diets)
+
+	if err != nil {
+		return domain.Menu{}, t.convertError(err)
+	}
+
+	return t.toDomain(result), nil
+}
+
+// GetRecipe はメニューの材料と調理手順を取得する
+func (t MenuGateway) GetRecipe(menuId uint) (domain.Recipe, error) {
+	result, err := t.menuDriver.GetRecipe(menuId)
+
+	if err != nil {
+		return domain.Recipe{}, t.convertError(err)
+	}
+
+	return t.toDomainRecipe(result), nil
+}
+
+// GetIngredients は複数メニューの材料をまとめて取得する
+// 存在しない・削除済みのメニューは結果に含めない
+func (t MenuGateway) GetIngredients(menuIds []uint) ([]domain.Recipe, error) {
+	results, err := t.menuDriver.GetIngredients(menuIds)
+
+	if err != nil {
+		return nil, t.convertError(err)
+	}
+
+	recipes := []domain.Recipe{}
+	for _, result := range results {
+		recipes = append(recipes, t.toDomainRecipe(result))
+	}
+
+	return recipes, nil
+}
+
+// UpdateRecipe はメニューの材料と調理手順を更新する
+func (t MenuGateway) UpdateRecipe(recipe domain.Recipe) (domain.Recipe, error) {
+	ingredients := []menu.MenuIngredient{}
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, menu.MenuIngredient{
+			Name:     ingredient.Name,
+			Quantity: ingredient.Quantity,
+			Unit:     ingredient.Unit,
+			Section:  ingredient.Section,
+		})
+	}
+
+	steps := []menu.MenuRecipeStep{}
+	for _, step := range recipe.Steps {
+		steps = append(steps, menu.MenuRecipeStep{
+			Instruction: step.Instruction,
+		})
+	}
+
+	result, err := t.menuDriver.UpdateRecipe(recipe.MenuId, ingredients, steps)
+
+	if err != nil {
+		return domain.Recipe{}, t.convertError(err)
+	}
+
+	return t.toDomainRecipe(result), nil
+}
+
 // DeleteMenu はメニューを削除する
 func (t MenuGateway) DeleteMenu(menuId uint) error {
 	err := t.menuDriver.DeleteMenu(menuId)
 
 	if err != nil {
-		return err
+		return t.convertError(err)
 	}
 
 	return nil
 }
 
+// RestoreMenu は論理削除したメニューを復元する
+func (t MenuGateway) RestoreMenu(menuId uint) (domain.Menu, error) {
+	result, err := t.menuDriver.RestoreMenu(menuId)
+
+	if err != nil {
+		return domain.Menu{}, t.convertError(err)
+	}
+
+	return t.toDomain(result), nil
+}
+
+// PurgeDeletedMenus は指定日時より前に論理削除したメニューを物理削除する
+func (t MenuGateway) PurgeDeletedMenus(deletedBefore time.Time) (int64, error) {
+	return t.menuDriver.PurgeDeletedMenus(deletedBefore)
+}
+
+// convertError はドライバーのエラーをドメインのエラーに変換する
+func (t MenuGateway) convertError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.NewNotFound("menu_not_found", "menu not found").Wrap(err)
+	}
+	if errors.Is(err, menu.ErrNotDeleted) {
+		return domain.NewConflict("menu_not_deleted", "menu is not deleted").Wrap(err)
+	}
+
+	return err
+}
+
+// toDomain はメニューのモデルをドメインモデルに変換する
+func (t MenuGateway) toDomain(result menu.Menu) domain.Menu {
+	return domain.Menu{
+		MenuId:         result.MenuId,
+		MenuName:       result.MenuName,
+		Description:    result.Description,
+		Price:          result.Price,
+		Calories:       result.Calories,
+		CookingMinutes: result.CookingMinutes,
+		ImageUrl:       result.ImageUrl,
+		GenreIds:       t.getRestGenreIds(result.Genres),
+		CategoryIds:    t.getRestCategoryIds(result.Categories),
+		Genres:         t.getRestGenres(result.Genres),
+		Categories:     t.getRestCategories(result.Categories),
+		Allergens:      t.getRestAllergens(result.Allergens),
+		Diets:          t.getRestDiets(result.Diets),
+		DeletedAt:      t.getDeletedAt(result.DeletedAt),
+	}
+}
+
+// getDeletedAt は論理削除した日時を取得する（削除されていない場合はnil）
+func (t MenuGateway) getDeletedAt(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+
+	return &deletedAt.Time
+}
+
+// toAttributes はドメインモデルからメニューの任意項目を取り出す
+func (t MenuGateway) toAttributes(m domain.Menu) menu.MenuAttributes {
+	return menu.MenuAttributes{
+		Description:    m.Description,
+		Price:          m.Price,
+		Calories:       m.Calories,
+		CookingMinutes: m.CookingMinutes,
+		ImageUrl:       m.ImageUrl,
+	}
+}
+
 // GetRestGenreIds はメニューに紐づくジャンルIDリストを取得する
 func (t MenuGateway) getRestGenreIds(genres []menu.Genre) []uint {
 	var genreIds []uint
@@ -138,3 +401,80 @@ func (t MenuGateway) getRestCategoryIds(categories []menu.Category) []uint {
 
 	return categoryIds
 }
+
+// getRestGenres はメニューに紐づくジャンルリストを取得する
+func (t MenuGateway) getRestGenres(genres []menu.Genre) []domain.Genre {
+	var restGenres []domain.Genre
+
+	for _, genre := range genres {
+		restGenres = append(restGenres, domain.Genre{
+			GenreId:   genre.GenreId,
+			GenreName: genre.GenreName,
+		})
+	}
+
+	return restGenres
+}
+
+// getRestCategories はメニューに紐づくカテゴリリストを取得する
+func (t MenuGateway) getRestCategories(categories []menu.Category) []domain.Category {
+	var restCategories []domain.Category
+
+	for _, category := range categories {
+		restCategories = append(restCategories, domain.Category{
+			CategoryId:   category.CategoryId,
+			CategoryName: category.CategoryName,
+		})
+	}
+
+	return restCategories
+}
+
+// getRestAllergens はメニューに含まれるアレルゲンのリストを取得する
+func (t MenuGateway) getRestAllergens(allergens []menu.MenuAllergen) []string {
+	restAllergens := []string{}
+
+	for _, allergen := range allergens {
+		restAllergens = append(restAllergens, allergen.Allergen)
+	}
+
+	return restAllergens
+}
+
+// getRestDiets はメニューが対応する食事制限のリストを取得する
+func (t MenuGateway) getRestDiets(diets []menu.MenuDiet) []string {
+	restDiets := []string{}
+
+	for _, diet := range diets {
+		restDiets = append(restDiets, diet.Diet)
+	}
+
+	return restDiets
+}
+
+// toDomainRecipe はレシピのモデルをドメインモデルに変換する
+func (t MenuGateway) toDomainRecipe(recipe menu.Recipe) domain.Recipe {
+	ingredients := []domain.Ingredient{}
+	for _, ingredient := range recipe.Ingredients {
+		ingredients = append(ingredients, domain.Ingredient{
+			Name:     ingredient.Name,
+			Quantity: ingredient.Quantity,
+			Unit:     ingredient.Unit,
+			Section:  ingredient.Section,
+		})
+	}
+
+	steps := []domain.RecipeStep{}
+	for _, step := range recipe.Steps {
+		steps = append(steps, domain.RecipeStep{
+			StepNumber:  step.StepNumber,
+			Instruction: step.Instruction,
+		})
+	}
+
+	return domain.Recipe{
+		MenuId:      recipe.MenuId,
+		Ingredients: ingredients,
+		Steps:       steps,
+	}
+}
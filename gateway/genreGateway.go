@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"errors"
+	"go-menu/domain"
+	"go-menu/resource/menu"
+	"go-menu/usecase/port"
+
+	"gorm.io/gorm"
+)
+
+type GenreGateway struct {
+	genreDriver menu.GenreDriver
+}
+
+func ProvideGenrePort(d menu.GenreDriver) port.GenrePort {
+	return &GenreGateway{d}
+}
+
+func (t GenreGateway) GetAll() ([]domain.Genre, error) {
+	results, err := t.genreDriver.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	genres := []domain.Genre{}
+	for _, result := range results {
+		genres = append(genres, t.toDomain(result))
+	}
+
+	return genres, nil
+}
+
+// GetByIds は指定したIDのジャンルを取得する
+func (t GenreGateway) GetByIds(genreIds []uint) ([]domain.Genre, error) {
+	results, err := t.genreDriver.GetByIds(genreIds)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := []domain.Genre{}
+	for _, result := range results {
+		genres = append(genres, t.toDomain(result))
+	}
+
+	return genres, nil
+}
+
+// CreateGenre はジャンルを作成する
+func (t GenreGateway) CreateGenre(genre domain.Genre) (domain.Genre, error) {
+	result, err := t.genreDriver.CreateGenre(genre.GenreName)
+	if err != nil {
+		return domain.Genre{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// UpdateGenre はジャンルを更新する
+func (t GenreGateway) UpdateGenre(genre domain.Genre) (domain.Genre, error) {
+	result, err := t.genreDriver.UpdateGenre(genre.GenreId, genre.GenreName)
+	if err != nil {
+		return domain.Genre{}, t.convertError(err)
+	}
+
+	return t.toDomain(result), nil
+}
+
+// DeleteGenre はジャンルを削除する
+func (t GenreGateway) DeleteGenre(genreId uint, cascade bool) error {
+	err := t.genreDriver.DeleteGenre(genreId, cascade)
+	if err != nil {
+		return t.convertError(err)
+	}
+
+	return nil
+}
+
+// toDomain はジャンルのモデルをドメインモデルに変換する
+func (t GenreGateway) toDomain(genre menu.Genre) domain.Genre {
+	return domain.Genre{
+		GenreId:   genre.GenreId,
+		GenreName: genre.GenreName,
+	}
+}
+
+// convertError はドライバーのエラーをドメインのエラーに変換する
+func (t GenreGateway) convertError(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return domain.NewNotFound("genre_not_found", "genre not found").Wrap(err)
+	case errors.Is(err, menu.ErrReferenced):
+		return domain.NewConflict("genre_in_use", "genre is referenced by menus").Wrap(err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,93 @@
+package gateway
+
+import (
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"go-menu/usecase/port"
+	"time"
+)
+
+type MealPlanGateway struct {
+	userDriver user.UserDriver
+}
+
+func ProvideMealPlanPort(d user.UserDriver) port.MealPlanPort {
+	return &MealPlanGateway{d}
+}
+
+// AddMealPlans は献立をまとめて追加する
+func (t MealPlanGateway) AddMealPlans(userId uint, entries []domain.MealPlanEntry) ([]domain.MealPlan, error) {
+	rows := []user.MealPlanEntry{}
+	for _, entry := range entries {
+		rows = append(rows, user.MealPlanEntry{
+			PlannedOn: entry.PlannedOn,
+			MealSlot:  entry.MealSlot,
+			MenuID:    entry.MenuID,
+		})
+	}
+
+	results, err := t.userDriver.AddMealPlans(userId, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.toDomainList(results), nil
+}
+
+// GetMealPlans は指定期間（両端を含む）の献立を取得する
+func (t MealPlanGateway) GetMealPlans(userId uint, from, to time.Time) ([]domain.MealPlan, error) {
+	results, err := t.userDriver.GetMealPlans(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.toDomainList(results), nil
+}
+
+// GetMealPlan は献立を取得する
+func (t MealPlanGateway) GetMealPlan(planId uint) (domain.MealPlan, error) {
+	result, err := t.userDriver.GetMealPlanByID(planId)
+	if err != nil {
+		return domain.MealPlan{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// MoveMealPlan は献立を別の日付・時間帯に移動する
+func (t MealPlanGateway) MoveMealPlan(planId uint, plannedOn time.Time, mealSlot string) (domain.MealPlan, error) {
+	result, err := t.userDriver.MoveMealPlan(planId, plannedOn, mealSlot)
+	if err != nil {
+		return domain.MealPlan{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// RemoveMealPlan は献立を削除する
+func (t MealPlanGateway) RemoveMealPlan(planId uint) error {
+	return t.userDriver.RemoveMealPlanByID(planId)
+}
+
+// toDomain は献立のモデルをドメインモデルに変換する
+func (t MealPlanGateway) toDomain(plan user.MealPlan) domain.MealPlan {
+	return domain.MealPlan{
+		PlanID:     plan.PlanID,
+		UserID:     plan.UserID,
+		MenuID:     plan.MenuID,
+		PlannedOn:  plan.PlannedOn.Format(domain.DateLayout),
+		MealSlot:   plan.MealSlot,
+		MenuName:   plan.MenuName,
+		MenuExists: plan.MenuExists,
+	}
+}
+
+// toDomainList は献立のモデルのリストをドメインモデルに変換する
+func (t MealPlanGateway) toDomainList(plans []user.MealPlan) []domain.MealPlan {
+	results := []domain.MealPlan{}
+	for _, plan := range plans {
+		results = append(results, t.toDomain(plan))
+	}
+
+	return results
+}
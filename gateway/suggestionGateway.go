@@ -0,0 +1,77 @@
+package gateway
+
+import (
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"go-menu/usecase/port"
+	"time"
+)
+
+type SuggestionGateway struct {
+	userDriver user.UserDriver
+}
+
+func ProvideSuggestionPort(d user.UserDriver) port.SuggestionPort {
+	return &SuggestionGateway{d}
+}
+
+// RecordSuggestions は提案したメニューを記録する
+func (t SuggestionGateway) RecordSuggestions(userId uint, menuIds []uint, suggestedAt time.Time) ([]domain.Suggestion, error) {
+	results, err := t.userDriver.RecordSuggestions(userId, menuIds, suggestedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.toDomainList(results), nil
+}
+
+// GetSuggestion は提案を取得する
+func (t SuggestionGateway) GetSuggestion(suggestionId uint) (domain.Suggestion, error) {
+	result, err := t.userDriver.GetSuggestionByID(suggestionId)
+	if err != nil {
+		return domain.Suggestion{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// AcceptSuggestion は提案を採用済みにする
+func (t SuggestionGateway) AcceptSuggestion(suggestionId uint, acceptedAt time.Time) (domain.Suggestion, error) {
+	result, err := t.userDriver.AcceptSuggestion(suggestionId, acceptedAt)
+	if err != nil {
+		return domain.Suggestion{}, err
+	}
+
+	return t.toDomain(result), nil
+}
+
+// GetAcceptedSuggestions は指定日時以降に採用した提案を新しい順に取得する
+func (t SuggestionGateway) GetAcceptedSuggestions(userId uint, since time.Time) ([]domain.Suggestion, error) {
+	results, err := t.userDriver.GetAcceptedSuggestions(userId, since)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.toDomainList(results), nil
+}
+
+// toDomain は提案のモデルをドメインモデルに変換する
+func (t SuggestionGateway) toDomain(suggestion user.Suggestion) domain.Suggestion {
+	return domain.Suggestion{
+		SuggestionID: suggestion.SuggestionID,
+		UserID:       suggestion.UserID,
+		MenuID:       suggestion.MenuID,
+		SuggestedAt:  suggestion.SuggestedAt,
+		AcceptedAt:   suggestion.AcceptedAt,
+	}
+}
+
+// toDomainList は提案のモデルのリストをドメインモデルに変換する
+func (t SuggestionGateway) toDomainList(suggestions []user.Suggestion) []domain.Suggestion {
+	results := []domain.Suggestion{}
+	for _, suggestion := range suggestions {
+		results = append(results, t.toDomain(suggestion))
+	}
+
+	return results
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// 購読URLのトークンに続く拡張子
+	calendarFeedSuffix = ".ics"
+	// APIの公開URLの既定値
+	defaultPublicBaseURL = "http://localhost:8080"
+)
+
+// CalendarConfig カレンダー購読の設定
+type CalendarConfig struct {
+	// 購読URLとイベントのUIDに使うAPIの公開URL（リクエストのHostヘッダーは信頼しない）
+	PublicBaseURL *url.URL
+}
+
+// NewCalendarConfig 環境変数（PUBLIC_BASE_URL）からカレンダー購読の設定を作成
+func NewCalendarConfig() CalendarConfig {
+	value := os.Getenv("PUBLIC_BASE_URL")
+	if value == "" {
+		log.Println("PUBLIC_BASE_URLが設定されていないため既定値を使用します: ", defaultPublicBaseURL)
+		value = defaultPublicBaseURL
+	}
+
+	baseURL, err := url.Parse(value)
+	if err != nil || (baseURL.Scheme != "http" && baseURL.Scheme != "https") || baseURL.Host == "" {
+		log.Fatal("PUBLIC_BASE_URLの形式が不正です: ", value)
+	}
+
+	return CalendarConfig{PublicBaseURL: baseURL}
+}
+
+// CalendarHandler 献立のカレンダー購読のHTTPハンドラー
+type CalendarHandler struct {
+	calendarUsecase usecase.CalendarUsecase
+	config          CalendarConfig
+}
+
+// ProvideCalendarHandler CalendarHandlerのコンストラクタ
+func ProvideCalendarHandler(u usecase.CalendarUsecase, config CalendarConfig) *CalendarHandler {
+	return &CalendarHandler{u, config}
+}
+
+// CalendarTokenResponse カレンダートークン発行レスポンス
+type CalendarTokenResponse struct {
+	// 購読用の秘密トークン（発行時にのみ返す）
+	Token string `json:"token"`
+	// カレンダーアプリに登録する購読URL
+	FeedURL string `json:"feed_url"`
+}
+
+// DeleteCalendarTokenResponse カレンダートークン無効化レスポンス
+type DeleteCalendarTokenResponse struct {
+	Success bool `json:"success"`
+}
+
+// IssueToken カレンダー購読用の秘密トークンを発行（既存のトークンは無効になる）
+func (h *CalendarHandler) IssueToken(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	token, err := h.calendarUsecase.IssueToken(userID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	feedURL := h.config.PublicBaseURL.JoinPath("v1", "calendar", token+calendarFeedSuffix)
+
+	response := CalendarTokenResponse{
+		Token:   token,
+		FeedURL: feedURL.String(),
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// RevokeToken カレンダー購読用の秘密トークンを無効化
+func (h *CalendarHandler) RevokeToken(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	if err := h.calendarUsecase.RevokeToken(userID); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := DeleteCalendarTokenResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetFeed 献立をiCalendar形式で返す（/v1/calendar/<token>.ics の秘密トークンで認証）
+// カレンダーアプリはAuthorizationヘッダーを送れないため、URLのトークンで利用者を特定する
+func (h *CalendarHandler) GetFeed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("feed"), calendarFeedSuffix)
+	if !ok {
+		abortWithError(c, domain.NewNotFound("calendar_feed_not_found", "calendar feed not found"))
+		return
+	}
+
+	h.writeFeed(c, token)
+}
+
+// GetLegacyFeed 発行済みの購読URL（/v1/calendar.ics?token=）のための献立のiCalendar
+func (h *CalendarHandler) GetLegacyFeed(c *gin.Context) {
+	h.writeFeed(c, c.Query("token"))
+}
+
+// writeFeed トークンの持ち主の献立をiCalendar形式で返す
+func (h *CalendarHandler) writeFeed(c *gin.Context, token string) {
+	if token == "" {
+		abortWithError(c, domain.NewUnauthorized("calendar_token_required", "token is required"))
+		return
+	}
+
+	plans, err := h.calendarUsecase.GetScheduledMenus(token)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "private, no-cache")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(buildICalendar(plans, h.config.PublicBaseURL.Hostname(), time.Now())))
+}
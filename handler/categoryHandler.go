@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CategoryHandler struct {
+	categoryUsecase usecase.CategoryUsecase
+}
+
+func ProvideCategoryHandler(u usecase.CategoryUsecase) *CategoryHandler {
+	return &CategoryHandler{u}
+}
+
+type CategoriesGetResponse struct {
+	Categories []domain.Category `json:"categories"`
+}
+
+type CategoryPostRequest struct {
+	CategoryName string `json:"category_name"`
+}
+
+type CategoryPostResponse struct {
+	Category domain.Category `json:"category"`
+}
+
+type CategoryPutRequest struct {
+	CategoryName string `json:"category_name"`
+}
+
+type CategoryPutResponse struct {
+	Category domain.Category `json:"category"`
+}
+
+func (h CategoryHandler) GetAll(c *gin.Context) {
+	categories, err := h.categoryUsecase.GetAll()
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := CategoriesGetResponse{
+		Categories: categories,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h CategoryHandler) CreateCategory(c *gin.Context) {
+	var req CategoryPostRequest
+	// リクエストボディを取得
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
+		return
+	}
+
+	if strings.TrimSpace(req.CategoryName) == "" {
+		abortWithError(c, domain.NewBadRequest("category_name_required", "category_name is required"))
+		return
+	}
+
+	// カテゴリを作成
+	category := domain.Category{
+		CategoryName: req.CategoryName,
+	}
+
+	createdCategory, err := h.categoryUsecase.CreateCategory(category)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := CategoryPostResponse{
+		Category: createdCategory,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h CategoryHandler) UpdateCategory(c *gin.Context) {
+	var req CategoryPutRequest
+	// リクエストボディを取得
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
+		return
+	}
+
+	if strings.TrimSpace(req.CategoryName) == "" {
+		abortWithError(c, domain.NewBadRequest("category_name_required", "category_name is required"))
+		return
+	}
+
+	// パスパラメータからcategory_idを取得
+	categoryId, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_category_id", "invalid category_id"))
+		return
+	}
+
+	// カテゴリを更新
+	category := domain.Category{
+		CategoryId:   uint(categoryId),
+		CategoryName: req.CategoryName,
+	}
+
+	updatedCategory, err := h.categoryUsecase.UpdateCategory(category)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := CategoryPutResponse{
+		Category: updatedCategory,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteCategory はカテゴリを削除する
+// メニューから参照されている場合は409を返し、cascade=trueの場合は関連ごと削除する
+func (h CategoryHandler) DeleteCategory(c *gin.Context) {
+	// パスパラメータからcategory_idを取得
+	categoryId, err := strconv.Atoi(c.Param("category_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_category_id", "invalid category_id"))
+		return
+	}
+
+	cascade, err := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_cascade", "invalid cascade"))
+		return
+	}
+
+	// カテゴリを削除
+	err = h.categoryUsecase.DeleteCategory(uint(categoryId), cascade)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "success",
+	})
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"errors"
+	"go-menu/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getUserID は認証ミドルウェアがコンテキストに設定したユーザーIDを取得する
+func getUserID(c *gin.Context) (uint, error) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		return 0, domain.NewUnauthorized("unauthenticated", "user not authenticated")
+	}
+
+	userIDUint, ok := userID.(uint)
+	if !ok {
+		return 0, errors.New("invalid user ID format")
+	}
+
+	return userIDUint, nil
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"go-menu/devauth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DevAuthHandler 開発用認証の公開鍵を提供するHTTPハンドラー
+type DevAuthHandler struct {
+	devAuth *devauth.DevAuth
+}
+
+// ProvideDevAuthHandler DevAuthHandlerのコンストラクタ
+func ProvideDevAuthHandler(devAuth *devauth.DevAuth) *DevAuthHandler {
+	return &DevAuthHandler{devAuth: devAuth}
+}
+
+// GetDiscovery OIDCディスカバリードキュメントを返す
+func (h *DevAuthHandler) GetDiscovery(c *gin.Context) {
+	c.JSON(http.StatusOK, h.devAuth.Discovery())
+}
+
+// GetJWKS 署名検証用の公開鍵を返す
+func (h *DevAuthHandler) GetJWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, h.devAuth.JWKS())
+}
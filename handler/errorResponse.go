@@ -0,0 +1,12 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// abortWithError はエラーをコンテキストに登録し、後続の処理を中断する
+// レスポンスは middleware.ErrorHandler で application/problem+json 形式に変換される
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
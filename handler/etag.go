@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// computeETag はレスポンスボディから強いETagを計算する
+func computeETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches はIf-None-Matchヘッダーが指定したETagに一致するかを判定する
+// 弱い比較（W/プレフィックスを無視）で判定する
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// respondWithETag はETagを付けてJSONを返す
+// If-None-Matchが一致する場合はボディなしで304を返す
+func respondWithETag(c *gin.Context, response any) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	etag := computeETag(body)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
@@ -4,21 +4,22 @@ import (
 	"errors"
 	"go-menu/domain"
 	"go-menu/resource/user"
+	"go-menu/usecase"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 )
 
 // FavoriteHandler お気に入り機能のHTTPハンドラー
 type FavoriteHandler struct {
-	userDriver user.UserDriver
+	userDriver      user.UserDriver
+	favoriteUsecase usecase.FavoriteUsecase
 }
 
 // ProvideFavoriteHandler FavoriteHandlerのコンストラクタ
-func ProvideFavoriteHandler(userDriver user.UserDriver) *FavoriteHandler {
-	return &FavoriteHandler{userDriver: userDriver}
+func ProvideFavoriteHandler(userDriver user.UserDriver, favoriteUsecase usecase.FavoriteUsecase) *FavoriteHandler {
+	return &FavoriteHandler{userDriver: userDriver, favoriteUsecase: favoriteUsecase}
 }
 
 // AddFavoriteRequest お気に入り追加リクエスト
@@ -34,6 +35,10 @@ type AddFavoriteResponse struct {
 // GetFavoritesResponse お気に入り一覧取得レスポンス
 type GetFavoritesResponse struct {
 	Favorites []domain.Favorites `json:"favorites"`
+	// 次のページを取得するためのカーソル（最後のページでは省略）
+	NextCursor string `json:"next_cursor,omitempty"`
+	// 条件に一致するお気に入りの件数
+	Total int64 `json:"total"`
 }
 
 // DeleteFavoriteResponse お気に入り削除レスポンス
@@ -46,47 +51,27 @@ func (h *FavoriteHandler) AddFavorite(c *gin.Context) {
 	// コンテキストからユーザーIDを取得
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"message": "User not authenticated",
-		})
+		abortWithError(c, domain.NewUnauthorized("unauthenticated", "user not authenticated"))
 		return
 	}
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Invalid user ID format",
-		})
+		abortWithError(c, errors.New("invalid user ID format"))
 		return
 	}
 
 	// リクエストボディから menu_id を取得
 	var req AddFavoriteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "Invalid request body: " + err.Error(),
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request body: "+err.Error()))
 		return
 	}
 
 	// お気に入りに追加
 	favorite, err := h.userDriver.AddFavorite(userIDUint, req.MenuID)
 	if err != nil {
-		// エラーメッセージによって適切なHTTPステータスコードを返す
-		switch err.Error() {
-		case "Menu is already in favorites":
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "Menu is already in favorites",
-			})
-		case "Menu not found":
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Menu not found",
-			})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": "Failed to add favorite: " + err.Error(),
-			})
-		}
+		abortWithError(c, err)
 		return
 	}
 
@@ -102,40 +87,35 @@ func (h *FavoriteHandler) GetFavorites(c *gin.Context) {
 	// コンテキストからユーザーIDを取得
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"message": "User not authenticated",
-		})
+		abortWithError(c, domain.NewUnauthorized("unauthenticated", "user not authenticated"))
 		return
 	}
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Invalid user ID format",
-		})
+		abortWithError(c, errors.New("invalid user ID format"))
 		return
 	}
 
-	// ユーザーのお気に入り一覧を取得
-	favorites, err := h.userDriver.GetUserFavorites(userIDUint)
+	query, err := parseFavoriteQuery(c, userIDUint)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "Failed to get favorites: " + err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
-	// domain.Favorites形式に変換
-	var domainFavorites []domain.Favorites
-	for _, fav := range favorites {
-		domainFavorites = append(domainFavorites, domain.Favorites{
-			FavoriteID: fav.FavoriteID,
-			MenuID:     fav.MenuID,
-		})
+	// ユーザーのお気に入り一覧をメニューとともに取得（食事制限プロファイルに反するメニューは除く）
+	page, err := h.favoriteUsecase.FindFavorites(query)
+	if err != nil {
+		abortWithError(c, err)
+		return
 	}
 
 	response := GetFavoritesResponse{
-		Favorites: domainFavorites,
+		Favorites: page.Favorites,
+		Total:     page.Total,
+	}
+	if page.Next != nil {
+		response.NextCursor = encodeFavoriteCursor(query.Sort, *page.Next)
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -146,17 +126,13 @@ func (h *FavoriteHandler) RemoveFavoriteByID(c *gin.Context) {
 	// コンテキストからユーザーIDを取得
 	userID, exists := c.Get("userID")
 	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "User not authenticated",
-		})
+		abortWithError(c, domain.NewUnauthorized("unauthenticated", "user not authenticated"))
 		return
 	}
 
 	userIDUint, ok := userID.(uint)
 	if !ok {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Invalid user ID format",
-		})
+		abortWithError(c, errors.New("invalid user ID format"))
 		return
 	}
 
@@ -164,41 +140,27 @@ func (h *FavoriteHandler) RemoveFavoriteByID(c *gin.Context) {
 	favoriteIDStr := c.Param("favoriteId")
 	favoriteID, err := strconv.ParseUint(favoriteIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid favorite ID",
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_favorite_id", "invalid favorite ID"))
 		return
 	}
 
 	// お気に入りが存在するかチェック
 	favorite, err := h.userDriver.GetFavoriteByID(uint(favoriteID))
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{
-				"error": "Favorite not found",
-			})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to get favorite: " + err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
 	// 削除権限チェック（自分のお気に入りのみ削除可能）
 	if favorite.UserID != userIDUint {
-		c.JSON(http.StatusForbidden, gin.H{
-			"error": "You can only delete your own favorites",
-		})
+		abortWithError(c, domain.NewForbidden("favorite_forbidden", "you can only delete your own favorites"))
 		return
 	}
 
 	// お気に入りを削除
 	err = h.userDriver.RemoveFavoriteByID(uint(favoriteID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "Failed to remove favorite: " + err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
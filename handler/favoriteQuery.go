@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"go-menu/domain"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// お気に入り一覧の1ページあたりのデフォルト件数
+	defaultFavoriteLimit = 50
+	// お気に入り一覧の1ページあたりの最大件数
+	maxFavoriteLimit = 200
+)
+
+// favoriteCursor はレスポンスのnext_cursorに埋め込むページ位置
+type favoriteCursor struct {
+	Sort domain.FavoriteSort `json:"sort"`
+	domain.FavoriteCursor
+}
+
+// parseFavoriteQuery はクエリパラメータからお気に入り一覧の検索条件を作成する
+func parseFavoriteQuery(c *gin.Context, userId uint) (domain.FavoriteQuery, error) {
+	query := domain.FavoriteQuery{
+		UserId: userId,
+		Limit:  defaultFavoriteLimit,
+		Sort:   domain.FavoriteSortCreatedDesc,
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxFavoriteLimit {
+			return domain.FavoriteQuery{}, domain.NewBadRequest("invalid_limit", "limit must be between 1 and "+strconv.Itoa(maxFavoriteLimit))
+		}
+		query.Limit = n
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		switch domain.FavoriteSort(sort) {
+		case domain.FavoriteSortCreatedAsc, domain.FavoriteSortCreatedDesc:
+			query.Sort = domain.FavoriteSort(sort)
+		default:
+			return domain.FavoriteQuery{}, domain.NewBadRequest("invalid_sort", "sort must be one of created_at, -created_at")
+		}
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := decodeFavoriteCursor(cursor, query.Sort)
+		if err != nil {
+			return domain.FavoriteQuery{}, err
+		}
+		query.After = &after
+	}
+
+	return query, nil
+}
+
+// encodeFavoriteCursor はページ位置をクライアントに返すカーソル文字列に変換する
+func encodeFavoriteCursor(sort domain.FavoriteSort, cursor domain.FavoriteCursor) string {
+	// 構造体のエンコードは失敗しない
+	b, _ := json.Marshal(favoriteCursor{Sort: sort, FavoriteCursor: cursor})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeFavoriteCursor はカーソル文字列をページ位置に変換する
+// 発行時と異なる並び順で使われた場合はエラーを返す
+func decodeFavoriteCursor(value string, sort domain.FavoriteSort) (domain.FavoriteCursor, error) {
+	invalid := domain.NewBadRequest("invalid_cursor", "invalid cursor")
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return domain.FavoriteCursor{}, invalid.Wrap(err)
+	}
+
+	var cursor favoriteCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return domain.FavoriteCursor{}, invalid.Wrap(err)
+	}
+	if cursor.Sort != sort {
+		return domain.FavoriteCursor{}, domain.NewBadRequest("invalid_cursor", "cursor was issued for a different sort order")
+	}
+
+	return cursor.FavoriteCursor, nil
+}
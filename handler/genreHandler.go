@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GenreHandler struct {
+	genreUsecase usecase.GenreUsecase
+}
+
+func ProvideGenreHandler(u usecase.GenreUsecase) *GenreHandler {
+	return &GenreHandler{u}
+}
+
+type GenresGetResponse struct {
+	Genres []domain.Genre `json:"genres"`
+}
+
+type GenrePostRequest struct {
+	GenreName string `json:"genre_name"`
+}
+
+type GenrePostResponse struct {
+	Genre domain.Genre `json:"genre"`
+}
+
+type GenrePutRequest struct {
+	GenreName string `json:"genre_name"`
+}
+
+type GenrePutResponse struct {
+	Genre domain.Genre `json:"genre"`
+}
+
+func (h GenreHandler) GetAll(c *gin.Context) {
+	genres, err := h.genreUsecase.GetAll()
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := GenresGetResponse{
+		Genres: genres,
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h GenreHandler) CreateGenre(c *gin.Context) {
+	var req GenrePostRequest
+	// リクエストボディを取得
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
+		return
+	}
+
+	if strings.TrimSpace(req.GenreName) == "" {
+		abortWithError(c, domain.NewBadRequest("genre_name_required", "genre_name is required"))
+		return
+	}
+
+	// ジャンルを作成
+	genre := domain.Genre{
+		GenreName: req.GenreName,
+	}
+
+	createdGenre, err := h.genreUsecase.CreateGenre(genre)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := GenrePostResponse{
+		Genre: createdGenre,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h GenreHandler) UpdateGenre(c *gin.Context) {
+	var req GenrePutRequest
+	// リクエストボディを取得
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
+		return
+	}
+
+	if strings.TrimSpace(req.GenreName) == "" {
+		abortWithError(c, domain.NewBadRequest("genre_name_required", "genre_name is required"))
+		return
+	}
+
+	// パスパラメータからgenre_idを取得
+	genreId, err := strconv.Atoi(c.Param("genre_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_genre_id", "invalid genre_id"))
+		return
+	}
+
+	// ジャンルを更新
+	genre := domain.Genre{
+		GenreId:   uint(genreId),
+		GenreName: req.GenreName,
+	}
+
+	updatedGenre, err := h.genreUsecase.UpdateGenre(genre)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := GenrePutResponse{
+		Genre: updatedGenre,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteGenre はジャンルを削除する
+// メニューから参照されている場合は409を返し、cascade=trueの場合は関連ごと削除する
+func (h GenreHandler) DeleteGenre(c *gin.Context) {
+	// パスパラメータからgenre_idを取得
+	genreId, err := strconv.Atoi(c.Param("genre_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_genre_id", "invalid genre_id"))
+		return
+	}
+
+	cascade, err := strconv.ParseBool(c.DefaultQuery("cascade", "false"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_cascade", "invalid cascade"))
+		return
+	}
+
+	// ジャンルを削除
+	err = h.genreUsecase.DeleteGenre(uint(genreId), cascade)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "success",
+	})
+}
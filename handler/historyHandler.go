@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HistoryHandler 食事履歴のHTTPハンドラー
+type HistoryHandler struct {
+	userDriver user.UserDriver
+}
+
+// ProvideHistoryHandler HistoryHandlerのコンストラクタ
+func ProvideHistoryHandler(userDriver user.UserDriver) *HistoryHandler {
+	return &HistoryHandler{userDriver: userDriver}
+}
+
+// AddHistoryRequest 食事履歴追加リクエスト
+type AddHistoryRequest struct {
+	MenuID   uint   `json:"menu_id" binding:"required"`
+	EatenOn  string `json:"eaten_on" binding:"required"`
+	MealSlot string `json:"meal_slot" binding:"required"`
+}
+
+// AddHistoryResponse 食事履歴追加レスポンス
+type AddHistoryResponse struct {
+	History domain.MealHistory `json:"history"`
+}
+
+// GetHistoriesResponse 食事履歴一覧取得レスポンス
+type GetHistoriesResponse struct {
+	Histories []domain.MealHistory `json:"histories"`
+}
+
+// DeleteHistoryResponse 食事履歴削除レスポンス
+type DeleteHistoryResponse struct {
+	Success bool `json:"success"`
+}
+
+// AddHistory 食事履歴を追加
+func (h *HistoryHandler) AddHistory(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req AddHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request body: "+err.Error()))
+		return
+	}
+
+	// 入力値の検証
+	var fields []domain.FieldError
+	eatenOn, err := time.ParseInLocation(domain.DateLayout, req.EatenOn, time.Local)
+	if err != nil {
+		fields = append(fields, domain.FieldError{Field: "eaten_on", Message: "must be a date in YYYY-MM-DD format"})
+	}
+	if !isMealSlot(req.MealSlot) {
+		fields = append(fields, domain.FieldError{Field: "meal_slot", Message: "must be one of breakfast, lunch, dinner"})
+	}
+	if len(fields) > 0 {
+		abortWithError(c, domain.NewValidation(fields))
+		return
+	}
+
+	// 食事履歴を追加
+	history, err := h.userDriver.AddMealHistory(userID, req.MenuID, eatenOn, req.MealSlot)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := AddHistoryResponse{
+		History: toDomainMealHistory(history),
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetHistories ユーザーの食事履歴を取得（?from=YYYY-MM-DD&to=YYYY-MM-DD で期間指定）
+func (h *HistoryHandler) GetHistories(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	from, err := parseDateQuery(c, "from")
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	to, err := parseDateQuery(c, "to")
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		abortWithError(c, domain.NewBadRequest("invalid_date_range", "from must not be after to"))
+		return
+	}
+
+	histories, err := h.userDriver.GetMealHistories(userID, from, to)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// domain.MealHistory形式に変換
+	domainHistories := []domain.MealHistory{}
+	for _, history := range histories {
+		domainHistories = append(domainHistories, toDomainMealHistory(history))
+	}
+
+	response := GetHistoriesResponse{
+		Histories: domainHistories,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveHistoryByID 食事履歴をIDで削除（権限チェック付き）
+func (h *HistoryHandler) RemoveHistoryByID(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// パスパラメータから history_id を取得
+	historyID, err := strconv.ParseUint(c.Param("historyId"), 10, 32)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_history_id", "invalid history ID"))
+		return
+	}
+
+	// 食事履歴が存在するかチェック
+	history, err := h.userDriver.GetMealHistoryByID(uint(historyID))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// 削除権限チェック（自分の履歴のみ削除可能）
+	if history.UserID != userID {
+		abortWithError(c, domain.NewForbidden("history_forbidden", "you can only delete your own meal history"))
+		return
+	}
+
+	if err := h.userDriver.RemoveMealHistoryByID(uint(historyID)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := DeleteHistoryResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// isMealSlot は食事の時間帯として有効な値かを判定する
+func isMealSlot(mealSlot string) bool {
+	switch mealSlot {
+	case domain.MealSlotBreakfast, domain.MealSlotLunch, domain.MealSlotDinner:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseDateQuery はYYYY-MM-DD形式の日付クエリを解析する（未指定の場合はゼロ値）
+func parseDateQuery(c *gin.Context, key string) (time.Time, error) {
+	value := c.Query(key)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.ParseInLocation(domain.DateLayout, value, time.Local)
+	if err != nil {
+		return time.Time{}, domain.NewBadRequest("invalid_"+key, key+" must be a date in YYYY-MM-DD format")
+	}
+
+	return date, nil
+}
+
+// toDomainMealHistory は食事履歴のモデルをドメインモデルに変換する
+func toDomainMealHistory(history user.MealHistory) domain.MealHistory {
+	return domain.MealHistory{
+		HistoryID: history.HistoryID,
+		MenuID:    history.MenuID,
+		EatenOn:   history.EatenOn.Format(domain.DateLayout),
+		MealSlot:  history.MealSlot,
+	}
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"fmt"
+	"go-menu/domain"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// iCalendarのPRODID
+	icalProductId = "-//go-menu//Menu Schedule//JA"
+	// 1行の最大オクテット数（RFC 5545 3.1）
+	icalMaxLineOctets = 75
+	// 1つの献立の予定の長さ
+	icalEventDuration = time.Hour
+)
+
+// icalSlotStart は食事の時間帯ごとの予定の開始時刻（時・分）
+var icalSlotStart = map[string][2]int{
+	domain.MealSlotBreakfast: {7, 0},
+	domain.MealSlotLunch:     {12, 0},
+	domain.MealSlotDinner:    {19, 0},
+}
+
+// icalTextEscaper はTEXT型の値をエスケープする（RFC 5545 3.3.11）
+var icalTextEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// buildICalendar は献立をRFC 5545形式のカレンダーに変換する
+// 献立1件を1つのVEVENTとし、SUMMARYにメニュー名を設定する
+// 時刻は利用者のタイムゾーンで解釈されるフローティング時刻とする
+func buildICalendar(plans []domain.MealPlan, host string, stamp time.Time) string {
+	var b strings.Builder
+
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:"+icalProductId)
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+icalTextEscaper.Replace("献立"))
+
+	for _, plan := range plans {
+		date, err := time.ParseInLocation(domain.DateLayout, plan.PlannedOn, time.Local)
+		if err != nil {
+			continue
+		}
+		clock := icalSlotStart[plan.MealSlot]
+		start := time.Date(date.Year(), date.Month(), date.Day(), clock[0], clock[1], 0, 0, time.Local)
+		end := start.Add(icalEventDuration)
+
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, fmt.Sprintf("UID:meal-plan-%d@%s", plan.PlanID, host))
+		writeICalLine(&b, "DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"))
+		writeICalLine(&b, "DTSTART:"+start.Format("20060102T150405"))
+		writeICalLine(&b, "DTEND:"+end.Format("20060102T150405"))
+		writeICalLine(&b, "SUMMARY:"+icalTextEscaper.Replace(plan.MenuName))
+		writeICalLine(&b, "CATEGORIES:"+icalTextEscaper.Replace(plan.MealSlot))
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+
+	return b.String()
+}
+
+// writeICalLine は1行をCRLF区切りで書き込み、75オクテットを超える場合は折り返す
+// 折り返しはUTF-8の文字の途中では行わない
+func writeICalLine(b *strings.Builder, line string) {
+	limit := icalMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// 継続行は先頭の空白1文字分短くする
+		limit = icalMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
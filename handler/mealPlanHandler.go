@@ -0,0 +1,304 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 一度に取得できる献立の最大日数
+const maxMealPlanDays = 31
+
+// MealPlanHandler 献立のHTTPハンドラー
+type MealPlanHandler struct {
+	mealPlanUsecase usecase.MealPlanUsecase
+}
+
+// ProvideMealPlanHandler MealPlanHandlerのコンストラクタ
+func ProvideMealPlanHandler(u usecase.MealPlanUsecase) *MealPlanHandler {
+	return &MealPlanHandler{u}
+}
+
+// AddMealPlanRequest 献立追加リクエスト
+type AddMealPlanRequest struct {
+	MenuID    uint   `json:"menu_id" binding:"required"`
+	PlannedOn string `json:"planned_on" binding:"required"`
+	MealSlot  string `json:"meal_slot" binding:"required"`
+}
+
+// MoveMealPlanRequest 献立移動リクエスト
+type MoveMealPlanRequest struct {
+	PlannedOn string `json:"planned_on" binding:"required"`
+	MealSlot  string `json:"meal_slot" binding:"required"`
+}
+
+// MealPlanResponse 献立1件のレスポンス
+type MealPlanResponse struct {
+	Plan domain.MealPlan `json:"plan"`
+}
+
+// GetMealPlansResponse 献立一覧取得レスポンス
+type GetMealPlansResponse struct {
+	Plans []domain.MealPlan `json:"plans"`
+}
+
+// AutoFillMealPlansResponse 献立の自動入力レスポンス
+type AutoFillMealPlansResponse struct {
+	Plans []domain.MealPlan `json:"plans"`
+	// 同じ結果を再現するためのシード
+	Seed int64 `json:"seed"`
+}
+
+// DeleteMealPlanResponse 献立削除レスポンス
+type DeleteMealPlanResponse struct {
+	Success bool `json:"success"`
+}
+
+// GetMealPlans ユーザーの献立を取得（?from=YYYY-MM-DD&to=YYYY-MM-DD で期間指定、省略時は今週）
+func (h *MealPlanHandler) GetMealPlans(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	from, err := parseDateQuery(c, "from")
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	to, err := parseDateQuery(c, "to")
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	if !to.IsZero() && from.IsZero() {
+		abortWithError(c, domain.NewBadRequest("invalid_date_range", "from is required when to is specified"))
+		return
+	}
+	if !from.IsZero() && !to.IsZero() {
+		if from.After(to) {
+			abortWithError(c, domain.NewBadRequest("invalid_date_range", "from must not be after to"))
+			return
+		}
+		if to.After(from.AddDate(0, 0, maxMealPlanDays-1)) {
+			abortWithError(c, domain.NewBadRequest("invalid_date_range", "date range must be at most "+strconv.Itoa(maxMealPlanDays)+" days"))
+			return
+		}
+	}
+
+	plans, err := h.mealPlanUsecase.GetMealPlans(userID, from, to)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := GetMealPlansResponse{
+		Plans: plans,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddMealPlan 献立を追加
+func (h *MealPlanHandler) AddMealPlan(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req AddMealPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request body: "+err.Error()))
+		return
+	}
+
+	plannedOn, err := parseMealPlanSlot(req.PlannedOn, req.MealSlot)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	plan, err := h.mealPlanUsecase.AddMealPlan(userID, domain.MealPlanEntry{
+		PlannedOn: plannedOn,
+		MealSlot:  req.MealSlot,
+		MenuID:    req.MenuID,
+	})
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := MealPlanResponse{
+		Plan: plan,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// MoveMealPlan 献立を別の日付・時間帯に移動（権限チェック付き）
+func (h *MealPlanHandler) MoveMealPlan(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// パスパラメータから plan_id を取得
+	planID, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_plan_id", "invalid plan ID"))
+		return
+	}
+
+	var req MoveMealPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request body: "+err.Error()))
+		return
+	}
+
+	plannedOn, err := parseMealPlanSlot(req.PlannedOn, req.MealSlot)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	plan, err := h.mealPlanUsecase.MoveMealPlan(userID, uint(planID), plannedOn, req.MealSlot)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := MealPlanResponse{
+		Plan: plan,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveMealPlanByID 献立をIDで削除（権限チェック付き）
+func (h *MealPlanHandler) RemoveMealPlanByID(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// パスパラメータから plan_id を取得
+	planID, err := strconv.ParseUint(c.Param("planId"), 10, 32)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_plan_id", "invalid plan ID"))
+		return
+	}
+
+	if err := h.mealPlanUsecase.RemoveMealPlan(userID, uint(planID)); err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := DeleteMealPlanResponse{
+		Success: true,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AutoFillWeek 指定日を含む週の空いている時間帯に献立を自動で入れる
+// クエリパラメータ
+//   - week_of=YYYY-MM-DD: 対象の週に含まれる日（省略時は今日）
+//   - slot=lunch,dinner: 対象の時間帯（省略時はすべて）
+//   - seed=N: 同じ結果を再現するためのシード
+func (h *MealPlanHandler) AutoFillWeek(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	day, err := parseDateQuery(c, "week_of")
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+	if day.IsZero() {
+		day = time.Now()
+	}
+
+	slots, err := parseMealSlots(c.QueryArray("slot"))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	seed := rand.Int64N(maxGeneratedSeed)
+	if value := c.Query("seed"); value != "" {
+		if seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			abortWithError(c, domain.NewBadRequest("invalid_seed", "invalid seed"))
+			return
+		}
+	}
+
+	plans, err := h.mealPlanUsecase.AutoFillWeek(userID, day, slots, seed)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := AutoFillMealPlansResponse{
+		Plans: plans,
+		Seed:  seed,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseMealPlanSlot は献立の日付と時間帯を検証する
+func parseMealPlanSlot(plannedOn string, mealSlot string) (time.Time, error) {
+	var fields []domain.FieldError
+	date, err := time.ParseInLocation(domain.DateLayout, plannedOn, time.Local)
+	if err != nil {
+		fields = append(fields, domain.FieldError{Field: "planned_on", Message: "must be a date in YYYY-MM-DD format"})
+	}
+	if !isMealSlot(mealSlot) {
+		fields = append(fields, domain.FieldError{Field: "meal_slot", Message: "must be one of breakfast, lunch, dinner"})
+	}
+	if len(fields) > 0 {
+		return time.Time{}, domain.NewValidation(fields)
+	}
+
+	return date, nil
+}
+
+// parseMealSlots は繰り返し指定またはカンマ区切りの時間帯指定を解析する（未指定の場合はすべて）
+func parseMealSlots(values []string) ([]string, error) {
+	selected := map[string]bool{}
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			if !isMealSlot(field) {
+				return nil, domain.NewBadRequest("invalid_slot", "slot must be one of breakfast, lunch, dinner")
+			}
+			selected[field] = true
+		}
+	}
+
+	// 1日の中の順に並べる
+	slots := []string{}
+	for _, slot := range domain.MealSlots {
+		if len(selected) == 0 || selected[slot] {
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots, nil
+}
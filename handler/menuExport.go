@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"go-menu/domain"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MenuExportItem エクスポートするメニュー（一括登録のJSONと同じ形式にmenu_idを加えたもの）
+type MenuExportItem struct {
+	MenuId         uint     `json:"menu_id"`
+	MenuName       string   `json:"menu_name"`
+	Description    string   `json:"description"`
+	Price          *uint    `json:"price"`
+	Calories       *uint    `json:"calories"`
+	CookingMinutes *uint    `json:"cooking_minutes"`
+	ImageUrl       string   `json:"image_url"`
+	Genres         []string `json:"genres"`
+	Categories     []string `json:"categories"`
+}
+
+// menuExporter はエクスポート形式ごとの書き出し処理
+type menuExporter interface {
+	begin() error
+	write(item MenuExportItem) error
+	end() error
+}
+
+// ExportMenus はすべてのメニューをジャンル名・カテゴリ名付きで書き出す
+// format=csv|json|ndjson（省略時はjson）
+// 全件をメモリに読み込まず、一定件数ずつ取得しながらレスポンスに書き出す
+func (h MenuHandler) ExportMenus(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+
+	var exporter menuExporter
+	var contentType string
+	switch format {
+	case "csv":
+		exporter = &csvMenuExporter{writer: csv.NewWriter(c.Writer)}
+		contentType = "text/csv; charset=utf-8"
+	case "json":
+		exporter = &jsonMenuExporter{writer: c.Writer}
+		contentType = "application/json; charset=utf-8"
+	case "ndjson":
+		exporter = &ndjsonMenuExporter{encoder: json.NewEncoder(c.Writer)}
+		contentType = "application/x-ndjson"
+	default:
+		abortWithError(c, domain.NewBadRequest("invalid_format", "format must be one of csv, json, ndjson"))
+		return
+	}
+
+	// レスポンスの書き出しは最初のバッチを取得できてから始める
+	// 書き出し開始前のエラーは通常のエラーレスポンスとして返す
+	started := false
+	start := func() error {
+		if started {
+			return nil
+		}
+		started = true
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", `attachment; filename="menus.`+format+`"`)
+		c.Status(http.StatusOK)
+		return exporter.begin()
+	}
+
+	err := h.menuUsecase.ExportMenus(func(menus []domain.Menu) error {
+		if err := start(); err != nil {
+			return err
+		}
+		for _, menu := range menus {
+			if err := exporter.write(toMenuExportItem(menu)); err != nil {
+				return err
+			}
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		if err = start(); err == nil {
+			err = exporter.end()
+		}
+	}
+	if err != nil {
+		if !started {
+			abortWithError(c, err)
+			return
+		}
+		// 書き出し開始後はステータスを変更できないため、接続を切断して不完全なデータであることを伝える
+		log.Println("メニューのエクスポートに失敗したため接続を切断します: ", err)
+		abortStream(c)
+	}
+}
+
+// abortStream は書き出し途中のレスポンスを終端せずに接続を切断する
+// チャンク形式の終端が届かないため、クライアントはレスポンスが不完全であることを検知できる
+func abortStream(c *gin.Context) {
+	c.Abort()
+	conn, _, err := c.Writer.Hijack()
+	if err != nil {
+		log.Println("エクスポートの接続を切断できませんでした: ", err)
+		return
+	}
+	if err := conn.Close(); err != nil {
+		log.Println("エクスポートの接続を切断できませんでした: ", err)
+	}
+}
+
+// toMenuExportItem はメニューをエクスポート形式に変換する
+func toMenuExportItem(menu domain.Menu) MenuExportItem {
+	item := MenuExportItem{
+		MenuId:         menu.MenuId,
+		MenuName:       menu.MenuName,
+		Description:    menu.Description,
+		Price:          menu.Price,
+		Calories:       menu.Calories,
+		CookingMinutes: menu.CookingMinutes,
+		ImageUrl:       menu.ImageUrl,
+		Genres:         []string{},
+		Categories:     []string{},
+	}
+	for _, genre := range menu.Genres {
+		item.Genres = append(item.Genres, genre.GenreName)
+	}
+	for _, category := range menu.Categories {
+		item.Categories = append(item.Categories, category.CategoryName)
+	}
+
+	return item
+}
+
+// csvMenuExporter はCSV形式で書き出す（一括登録のCSVと同じ列）
+type csvMenuExporter struct {
+	writer *csv.Writer
+}
+
+func (e *csvMenuExporter) begin() error {
+	return e.writer.Write(importCSVColumns)
+}
+
+func (e *csvMenuExporter) write(item MenuExportItem) error {
+	err := e.writer.Write([]string{
+		strconv.FormatUint(uint64(item.MenuId), 10),
+		item.MenuName,
+		item.Description,
+		formatOptionalUint(item.Price),
+		formatOptionalUint(item.Calories),
+		formatOptionalUint(item.CookingMinutes),
+		item.ImageUrl,
+		strings.Join(item.Genres, importListSeparator),
+		strings.Join(item.Categories, importListSeparator),
+	})
+	if err != nil {
+		return err
+	}
+	// バッチごとのFlushでレスポンスに届くよう、CSVのバッファも書き出す
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+func (e *csvMenuExporter) end() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// jsonMenuExporter はJSON配列として書き出す
+type jsonMenuExporter struct {
+	writer io.Writer
+	count  int
+}
+
+func (e *jsonMenuExporter) begin() error {
+	_, err := io.WriteString(e.writer, "[")
+	return err
+}
+
+func (e *jsonMenuExporter) write(item MenuExportItem) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	if e.count > 0 {
+		if _, err := io.WriteString(e.writer, ","); err != nil {
+			return err
+		}
+	}
+	e.count++
+	_, err = e.writer.Write(data)
+	return err
+}
+
+func (e *jsonMenuExporter) end() error {
+	_, err := io.WriteString(e.writer, "]\n")
+	return err
+}
+
+// ndjsonMenuExporter は1行に1メニューのJSONとして書き出す
+type ndjsonMenuExporter struct {
+	encoder *json.Encoder
+}
+
+func (e *ndjsonMenuExporter) begin() error {
+	return nil
+}
+
+func (e *ndjsonMenuExporter) write(item MenuExportItem) error {
+	return e.encoder.Encode(item)
+}
+
+func (e *ndjsonMenuExporter) end() error {
+	return nil
+}
+
+// formatOptionalUint は未設定の数値を空文字列として書き出す
+func formatOptionalUint(value *uint) string {
+	if value == nil {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(*value), 10)
+}
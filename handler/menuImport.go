@@ -0,0 +1,269 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-menu/domain"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/encoding/japanese"
+)
+
+const (
+	// 一括登録のリクエストボディの最大サイズ
+	maxImportBodyBytes = 10 << 20
+	// CSVでジャンル・カテゴリを複数指定する場合の区切り文字
+	importListSeparator = "|"
+)
+
+// importCSVColumns はCSVで指定できる列
+// menu_idはエクスポートしたCSVをそのまま読み込めるよう受け付けるが、値は使わない
+var importCSVColumns = []string{"menu_id", "menu_name", "description", "price", "calories", "cooking_minutes", "image_url", "genres", "categories"}
+
+// MenuImportItem JSONで一括登録するメニュー
+// エクスポートしたJSONのmenu_idは無視する
+type MenuImportItem struct {
+	MenuName       string   `json:"menu_name"`
+	Description    string   `json:"description"`
+	Price          *uint    `json:"price"`
+	Calories       *uint    `json:"calories"`
+	CookingMinutes *uint    `json:"cooking_minutes"`
+	ImageUrl       string   `json:"image_url"`
+	Genres         []string `json:"genres"`
+	Categories     []string `json:"categories"`
+}
+
+// MenuImportResponse 一括登録レスポンス
+type MenuImportResponse struct {
+	Result domain.MenuImportResult `json:"result"`
+}
+
+// ImportMenus はCSVまたはJSON配列からメニューを一括登録する
+// 形式はformatクエリ（csv|json）またはContent-Typeで判定する
+// CSVはUTF-8（BOM付きを含む）とShift_JISに対応し、1行目をヘッダーとして扱う
+// dry_run=trueの場合は登録せずに行ごとの検証結果を返す
+func (h MenuHandler) ImportMenus(c *gin.Context) {
+	dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_dry_run", "dry_run must be a boolean"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBodyBytes))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "failed to read request body: "+err.Error()))
+		return
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	format := c.Query("format")
+	if format == "" {
+		switch mediaType {
+		case "text/csv", "application/csv", "application/vnd.ms-excel":
+			format = "csv"
+		default:
+			format = "json"
+		}
+	}
+
+	var rows []domain.MenuImportRow
+	switch format {
+	case "csv":
+		rows, err = parseImportCSV(body, params["charset"])
+	case "json":
+		rows, err = parseImportJSON(body)
+	default:
+		err = domain.NewBadRequest("invalid_format", "format must be csv or json")
+	}
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	result, err := h.menuUsecase.ImportMenus(rows, dryRun)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := MenuImportResponse{
+		Result: result,
+	}
+
+	status := http.StatusCreated
+	if dryRun {
+		status = http.StatusOK
+	}
+	c.JSON(status, response)
+}
+
+// parseImportCSV はCSVを一括登録の行に変換する
+// charsetが未指定でUTF-8として不正な場合はShift_JISとして読み込む
+func parseImportCSV(body []byte, charset string) ([]domain.MenuImportRow, error) {
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+
+	switch strings.ToLower(charset) {
+	case "shift_jis", "shift-jis", "sjis", "windows-31j", "cp932":
+		decoded, ok := decodeShiftJIS(body)
+		if !ok {
+			return nil, domain.NewBadRequest("invalid_encoding", "csv is not valid Shift_JIS")
+		}
+		body = decoded
+	case "", "utf-8", "utf8":
+		if !utf8.Valid(body) {
+			decoded, ok := decodeShiftJIS(body)
+			if !ok {
+				return nil, domain.NewBadRequest("invalid_encoding", "csv must be encoded in UTF-8 or Shift_JIS")
+			}
+			body = decoded
+		}
+	default:
+		return nil, domain.NewBadRequest("invalid_encoding", "charset must be utf-8 or shift_jis")
+	}
+
+	reader := csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return []domain.MenuImportRow{}, nil
+	}
+	if err != nil {
+		return nil, domain.NewBadRequest("invalid_csv", "invalid csv: "+err.Error())
+	}
+
+	// ヘッダーから列の位置を決める
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isImportCSVColumn(name) {
+			return nil, domain.NewBadRequest("invalid_csv_header", fmt.Sprintf("unknown column: %s (allowed: %s)", name, strings.Join(importCSVColumns, ", ")))
+		}
+		if _, ok := columns[name]; ok {
+			return nil, domain.NewBadRequest("invalid_csv_header", "duplicate column: "+name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["menu_name"]; !ok {
+		return nil, domain.NewBadRequest("invalid_csv_header", "menu_name column is required")
+	}
+
+	rows := []domain.MenuImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, domain.NewBadRequest("invalid_csv", "invalid csv: "+err.Error())
+		}
+		line, _ := reader.FieldPos(0)
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row := domain.MenuImportRow{
+			Row:           line,
+			MenuName:      value("menu_name"),
+			Description:   value("description"),
+			ImageUrl:      value("image_url"),
+			GenreNames:    splitImportList(value("genres")),
+			CategoryNames: splitImportList(value("categories")),
+		}
+		numbers := []struct {
+			column string
+			target **uint
+		}{
+			{"price", &row.Price},
+			{"calories", &row.Calories},
+			{"cooking_minutes", &row.CookingMinutes},
+		}
+		for _, number := range numbers {
+			raw := value(number.column)
+			if raw == "" {
+				continue
+			}
+			n, err := strconv.ParseUint(raw, 10, 32)
+			if err != nil {
+				row.Errors = append(row.Errors, domain.FieldError{Field: number.column, Message: "must be a non-negative integer"})
+				continue
+			}
+			parsed := uint(n)
+			*number.target = &parsed
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseImportJSON はJSON配列を一括登録の行に変換する
+func parseImportJSON(body []byte) ([]domain.MenuImportRow, error) {
+	var items []MenuImportItem
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, domain.NewBadRequest("invalid_request", "request body must be a JSON array of menus: "+err.Error())
+	}
+
+	rows := []domain.MenuImportRow{}
+	for i, item := range items {
+		rows = append(rows, domain.MenuImportRow{
+			Row:            i + 1,
+			MenuName:       item.MenuName,
+			Description:    item.Description,
+			Price:          item.Price,
+			Calories:       item.Calories,
+			CookingMinutes: item.CookingMinutes,
+			ImageUrl:       item.ImageUrl,
+			GenreNames:     item.Genres,
+			CategoryNames:  item.Categories,
+		})
+	}
+
+	return rows, nil
+}
+
+// decodeShiftJIS はShift_JISをUTF-8に変換する
+// デコーダーは不正なバイト列を置換文字（U+FFFD）に置き換えるため、置換文字を含む場合も失敗として扱う
+func decodeShiftJIS(body []byte) ([]byte, bool) {
+	decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(body)
+	if err != nil || bytes.ContainsRune(decoded, utf8.RuneError) {
+		return nil, false
+	}
+
+	return decoded, true
+}
+
+// isImportCSVColumn はCSVで指定できる列かを判定する
+func isImportCSVColumn(name string) bool {
+	for _, column := range importCSVColumns {
+		if column == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitImportList は区切り文字で区切られた名前のリストを分割する
+func splitImportList(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, importListSeparator)
+}
@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"go-menu/domain"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// メニュー一覧の1ページあたりのデフォルト件数
+	defaultMenuLimit = 100
+	// メニュー一覧の1ページあたりの最大件数
+	maxMenuLimit = 500
+	// ランダムに選ぶ最大件数
+	maxRandomCount = 20
+	// 生成するシードの上限（JavaScriptの数値で安全に扱える範囲）
+	maxGeneratedSeed = 1 << 53
+)
+
+// menuCursor はレスポンスのnext_cursorに埋め込むページ位置
+type menuCursor struct {
+	Sort domain.MenuSort `json:"sort"`
+	domain.MenuCursor
+}
+
+// parseMenuQuery はメニュー一覧のクエリパラメータを解析する
+// 例: ?limit=20&cursor=...&sort=-menu_id&genre_id=1,2&genre_match=all&category_id=3&q=カレー&max_price=800
+func parseMenuQuery(c *gin.Context) (domain.MenuQuery, error) {
+	query := domain.MenuQuery{
+		Limit:         defaultMenuLimit,
+		Sort:          domain.MenuSortIdAsc,
+		GenreMatch:    domain.MatchAny,
+		CategoryMatch: domain.MatchAny,
+		Keyword:       strings.TrimSpace(c.Query("q")),
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxMenuLimit {
+			return domain.MenuQuery{}, domain.NewBadRequest("invalid_limit", "limit must be between 1 and "+strconv.Itoa(maxMenuLimit))
+		}
+		query.Limit = n
+	}
+
+	if sort := c.Query("sort"); sort != "" {
+		switch domain.MenuSort(sort) {
+		case domain.MenuSortIdAsc, domain.MenuSortIdDesc, domain.MenuSortNameAsc, domain.MenuSortNameDesc:
+			query.Sort = domain.MenuSort(sort)
+		default:
+			return domain.MenuQuery{}, domain.NewBadRequest("invalid_sort", "sort must be one of menu_id, -menu_id, menu_name, -menu_name")
+		}
+	}
+
+	var err error
+	if query.GenreIds, err = parseUintList(c.QueryArray("genre_id")); err != nil {
+		return domain.MenuQuery{}, domain.NewBadRequest("invalid_genre_id", "invalid genre_id")
+	}
+	if query.CategoryIds, err = parseUintList(c.QueryArray("category_id")); err != nil {
+		return domain.MenuQuery{}, domain.NewBadRequest("invalid_category_id", "invalid category_id")
+	}
+	if query.GenreMatch, err = parseMatchMode(c.Query("genre_match")); err != nil {
+		return domain.MenuQuery{}, domain.NewBadRequest("invalid_genre_match", "genre_match must be any or all")
+	}
+	if query.CategoryMatch, err = parseMatchMode(c.Query("category_match")); err != nil {
+		return domain.MenuQuery{}, domain.NewBadRequest("invalid_category_match", "category_match must be any or all")
+	}
+
+	if query.MaxPrice, err = parseOptionalUint(c, "max_price"); err != nil {
+		return domain.MenuQuery{}, err
+	}
+	if query.MaxCalories, err = parseOptionalUint(c, "max_calories"); err != nil {
+		return domain.MenuQuery{}, err
+	}
+	if query.MaxMinutes, err = parseOptionalUint(c, "max_minutes"); err != nil {
+		return domain.MenuQuery{}, err
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		after, err := decodeMenuCursor(cursor, query.Sort)
+		if err != nil {
+			return domain.MenuQuery{}, err
+		}
+		query.After = &after
+	}
+
+	// ログイン時は食事制限プロファイルに反するメニューを除外する
+	if userID, ok := c.Get("userID"); ok {
+		query.UserId, _ = userID.(uint)
+	}
+
+	// 論理削除したメニューは管理者のみ取得できる
+	if value := c.Query("include_deleted"); value != "" {
+		if query.IncludeDeleted, err = strconv.ParseBool(value); err != nil {
+			return domain.MenuQuery{}, domain.NewBadRequest("invalid_include_deleted", "include_deleted must be a boolean")
+		}
+		if query.IncludeDeleted && c.GetString("role") != domain.RoleAdmin {
+			if _, ok := c.Get("userID"); !ok {
+				return domain.MenuQuery{}, domain.NewUnauthorized("authorization_required", "include_deleted requires authentication")
+			}
+			return domain.MenuQuery{}, domain.NewForbidden("admin_required", "include_deleted requires administrator privileges")
+		}
+	}
+
+	return query, nil
+}
+
+// parseRandomMenuQuery はランダム選択のクエリパラメータを解析する
+// 例: ?count=3&genre_id=1&exclude=4,5&seed=42&favorite_weight=3
+func parseRandomMenuQuery(c *gin.Context) (domain.RandomMenuQuery, error) {
+	query := domain.RandomMenuQuery{
+		Count:          1,
+		FavoriteWeight: 1,
+	}
+
+	var err error
+	if query.GenreIds, err = parseUintList(c.QueryArray("genre_id")); err != nil {
+		return domain.RandomMenuQuery{}, domain.NewBadRequest("invalid_genre_id", "invalid genre_id")
+	}
+	if query.CategoryIds, err = parseUintList(c.QueryArray("category_id")); err != nil {
+		return domain.RandomMenuQuery{}, domain.NewBadRequest("invalid_category_id", "invalid category_id")
+	}
+	if query.ExcludeIds, err = parseUintList(c.QueryArray("exclude")); err != nil {
+		return domain.RandomMenuQuery{}, domain.NewBadRequest("invalid_exclude", "invalid exclude")
+	}
+
+	if count := c.Query("count"); count != "" {
+		n, err := strconv.Atoi(count)
+		if err != nil || n < 1 || n > maxRandomCount {
+			return domain.RandomMenuQuery{}, domain.NewBadRequest("invalid_count", "count must be between 1 and "+strconv.Itoa(maxRandomCount))
+		}
+		query.Count = n
+	}
+
+	if seed := c.Query("seed"); seed != "" {
+		if query.Seed, err = strconv.ParseInt(seed, 10, 64); err != nil {
+			return domain.RandomMenuQuery{}, domain.NewBadRequest("invalid_seed", "invalid seed")
+		}
+	} else {
+		query.Seed = rand.Int64N(maxGeneratedSeed)
+	}
+
+	if weight := c.Query("favorite_weight"); weight != "" {
+		if query.FavoriteWeight, err = strconv.ParseFloat(weight, 64); err != nil || query.FavoriteWeight < 1 || query.FavoriteWeight > 100 {
+			return domain.RandomMenuQuery{}, domain.NewBadRequest("invalid_favorite_weight", "favorite_weight must be between 1 and 100")
+		}
+		// お気に入りの重み付けにはログインが必要
+		if _, ok := c.Get("userID"); !ok {
+			return domain.RandomMenuQuery{}, domain.NewUnauthorized("authorization_required", "favorite_weight requires authentication")
+		}
+	}
+
+	// ログイン時は食事制限プロファイルとお気に入りを参照する
+	if userID, ok := c.Get("userID"); ok {
+		query.UserId, _ = userID.(uint)
+	}
+
+	return query, nil
+}
+
+// parseUintList は繰り返し指定またはカンマ区切りのID指定を解析する
+func parseUintList(values []string) ([]uint, error) {
+	var ids []uint
+
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+
+	return ids, nil
+}
+
+// parseOptionalUint は任意指定の0以上の整数クエリを解析する（未指定の場合はnil）
+func parseOptionalUint(c *gin.Context, key string) (*uint, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	n, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, domain.NewBadRequest("invalid_"+key, key+" must be a non-negative integer")
+	}
+	result := uint(n)
+
+	return &result, nil
+}
+
+// parseMatchMode は一致条件（any/all）を解析する
+func parseMatchMode(value string) (domain.MatchMode, error) {
+	switch domain.MatchMode(value) {
+	case "", domain.MatchAny:
+		return domain.MatchAny, nil
+	case domain.MatchAll:
+		return domain.MatchAll, nil
+	default:
+		return "", domain.NewBadRequest("invalid_match", "invalid match mode")
+	}
+}
+
+// encodeMenuCursor はページ位置をクライアントに返すカーソル文字列に変換する
+func encodeMenuCursor(sort domain.MenuSort, cursor domain.MenuCursor) string {
+	// 構造体のエンコードは失敗しない
+	b, _ := json.Marshal(menuCursor{Sort: sort, MenuCursor: cursor})
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeMenuCursor はカーソル文字列をページ位置に変換する
+// 発行時と異なる並び順で使われた場合はエラーを返す
+func decodeMenuCursor(value string, sort domain.MenuSort) (domain.MenuCursor, error) {
+	invalid := domain.NewBadRequest("invalid_cursor", "invalid cursor")
+
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return domain.MenuCursor{}, invalid.Wrap(err)
+	}
+
+	var cursor menuCursor
+	if err := json.Unmarshal(b, &cursor); err != nil {
+		return domain.MenuCursor{}, invalid.Wrap(err)
+	}
+	if cursor.Sort != sort {
+		return domain.MenuCursor{}, domain.NewBadRequest("invalid_cursor", "cursor was issued for a different sort order")
+	}
+
+	return cursor.MenuCursor, nil
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProfileHandler 食事制限プロファイルのHTTPハンドラー
+type ProfileHandler struct {
+	profileUsecase usecase.DietaryProfileUsecase
+}
+
+// ProvideProfileHandler ProfileHandlerのコンストラクタ
+func ProvideProfileHandler(u usecase.DietaryProfileUsecase) *ProfileHandler {
+	return &ProfileHandler{profileUsecase: u}
+}
+
+// DietaryProfilePutRequest 食事制限プロファイル更新リクエスト
+type DietaryProfilePutRequest struct {
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+}
+
+// DietaryProfileResponse 食事制限プロファイルのレスポンス
+type DietaryProfileResponse struct {
+	Profile domain.DietaryProfile `json:"profile"`
+}
+
+// GetDietaryProfile ログインユーザーの食事制限プロファイルを取得
+func (h *ProfileHandler) GetDietaryProfile(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	profile, err := h.profileUsecase.GetDietaryProfile(userID)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := DietaryProfileResponse{
+		Profile: profile,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateDietaryProfile ログインユーザーの食事制限プロファイルを置き換える
+func (h *ProfileHandler) UpdateDietaryProfile(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	var req DietaryProfilePutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request body: "+err.Error()))
+		return
+	}
+
+	profile := domain.DietaryProfile{
+		Allergens: req.Allergens,
+		Diets:     req.Diets,
+	}
+
+	updatedProfile, err := h.profileUsecase.UpdateDietaryProfile(userID, profile)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := DietaryProfileResponse{
+		Profile: updatedProfile,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
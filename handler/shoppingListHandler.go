@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ShoppingListHandler 買い物リストのHTTPハンドラー
+type ShoppingListHandler struct {
+	shoppingListUsecase usecase.ShoppingListUsecase
+}
+
+// ProvideShoppingListHandler ShoppingListHandlerのコンストラクタ
+func ProvideShoppingListHandler(u usecase.ShoppingListUsecase) *ShoppingListHandler {
+	return &ShoppingListHandler{shoppingListUsecase: u}
+}
+
+// ShoppingListItemRequest 買い物リストの対象メニュー
+type ShoppingListItemRequest struct {
+	MenuID   uint `json:"menu_id"`
+	Servings uint `json:"servings"`
+}
+
+// ShoppingListPostRequest 買い物リスト作成リクエスト
+type ShoppingListPostRequest struct {
+	Items []ShoppingListItemRequest `json:"items"`
+}
+
+// ShoppingListPostResponse 買い物リスト作成レスポンス
+type ShoppingListPostResponse struct {
+	ShoppingList domain.ShoppingList `json:"shopping_list"`
+}
+
+// CreateShoppingList メニューと人数から買い物リストを作成
+func (h *ShoppingListHandler) CreateShoppingList(c *gin.Context) {
+	var req ShoppingListPostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request body: "+err.Error()))
+		return
+	}
+
+	var items []domain.ShoppingListItem
+	for _, item := range req.Items {
+		items = append(items, domain.ShoppingListItem{
+			MenuId:   item.MenuID,
+			Servings: item.Servings,
+		})
+	}
+
+	list, err := h.shoppingListUsecase.CreateShoppingList(items)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := ShoppingListPostResponse{
+		ShoppingList: list,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
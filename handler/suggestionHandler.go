@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 一度に提案する最大件数
+const maxSuggestionCount = 10
+
+// SuggestionHandler メニュー提案のHTTPハンドラー
+type SuggestionHandler struct {
+	suggestionUsecase usecase.SuggestionUsecase
+}
+
+// ProvideSuggestionHandler SuggestionHandlerのコンストラクタ
+func ProvideSuggestionHandler(u usecase.SuggestionUsecase) *SuggestionHandler {
+	return &SuggestionHandler{u}
+}
+
+// SuggestionsPostResponse メニュー提案レスポンス
+type SuggestionsPostResponse struct {
+	Suggestions []domain.Suggestion `json:"suggestions"`
+	// 同じ結果を再現するためのシード
+	Seed int64 `json:"seed"`
+}
+
+// SuggestionAcceptResponse 提案採用レスポンス
+type SuggestionAcceptResponse struct {
+	Suggestion domain.Suggestion `json:"suggestion"`
+}
+
+// Suggest ルールを満たすメニューを提案
+// ルールはクエリパラメータで指定する
+//   - no_repeat_days=N: 直近N日以内に採用したメニューを提案しない
+//   - no_same_genre=true: 直前と同じジャンルのメニューを続けて提案しない
+func (h *SuggestionHandler) Suggest(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	rules, err := parseSuggestionRules(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	count := 1
+	if value := c.Query("count"); value != "" {
+		count, err = strconv.Atoi(value)
+		if err != nil || count < 1 || count > maxSuggestionCount {
+			abortWithError(c, domain.NewBadRequest("invalid_count", "count must be between 1 and "+strconv.Itoa(maxSuggestionCount)))
+			return
+		}
+	}
+
+	seed := rand.Int64N(maxGeneratedSeed)
+	if value := c.Query("seed"); value != "" {
+		if seed, err = strconv.ParseInt(value, 10, 64); err != nil {
+			abortWithError(c, domain.NewBadRequest("invalid_seed", "invalid seed"))
+			return
+		}
+	}
+
+	suggestions, err := h.suggestionUsecase.Suggest(userID, rules, count, seed)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := SuggestionsPostResponse{
+		Suggestions: suggestions,
+		Seed:        seed,
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// Accept 提案を採用
+func (h *SuggestionHandler) Accept(c *gin.Context) {
+	userID, err := getUserID(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	// パスパラメータから suggestion_id を取得
+	suggestionID, err := strconv.ParseUint(c.Param("suggestionId"), 10, 32)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_suggestion_id", "invalid suggestion ID"))
+		return
+	}
+
+	suggestion, err := h.suggestionUsecase.Accept(userID, uint(suggestionID))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := SuggestionAcceptResponse{
+		Suggestion: suggestion,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseSuggestionRules はクエリパラメータから提案のルールを組み立てる
+func parseSuggestionRules(c *gin.Context) (usecase.SuggestionRules, error) {
+	var rules usecase.SuggestionRules
+
+	if value := c.Query("no_repeat_days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 1 || days > 365 {
+			return nil, domain.NewBadRequest("invalid_no_repeat_days", "no_repeat_days must be between 1 and 365")
+		}
+		rules = append(rules, usecase.NoRecentAcceptedRule{Days: days})
+	}
+
+	if value := c.Query("no_same_genre"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, domain.NewBadRequest("invalid_no_same_genre", "no_same_genre must be a boolean")
+		}
+		if enabled {
+			rules = append(rules, usecase.NoSameGenreInARowRule{})
+		}
+	}
+
+	return rules, nil
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"fmt"
 	"go-menu/domain"
 	"go-menu/usecase"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -19,12 +21,31 @@ func ProvideMenuHandler(u usecase.MenuUsecase) *MenuHandler {
 
 type MenusGetResponse struct {
 	Menus []domain.Menu `json:"menus"`
+	// 次のページを取得するためのカーソル（最後のページでは省略）
+	NextCursor string `json:"next_cursor,omitempty"`
+	// 検索条件に一致するメニューの総数
+	Total int64 `json:"total"`
+}
+
+type MenuGetResponse struct {
+	Menu domain.Menu `json:"menu"`
+}
+
+type MenusRandomResponse struct {
+	Menus []domain.Menu `json:"menus"`
+	// 同じ結果を再現するためのシード
+	Seed int64 `json:"seed"`
 }
 
 type MenuPostRequest struct {
-	MenuName    string `json:"menu_name"`
-	GenreIds    []uint `json:"genre_ids"`
-	CategoryIds []uint `json:"category_ids"`
+	MenuName       string `json:"menu_name"`
+	Description    string `json:"description"`
+	Price          *uint  `json:"price"`
+	Calories       *uint  `json:"calories"`
+	CookingMinutes *uint  `json:"cooking_minutes"`
+	ImageUrl       string `json:"image_url"`
+	GenreIds       []uint `json:"genre_ids"`
+	CategoryIds    []uint `json:"category_ids"`
 }
 
 type MenuPostResponse struct {
@@ -32,9 +53,14 @@ type MenuPostResponse struct {
 }
 
 type MenuPutRequest struct {
-	MenuName    string `json:"menu_name"`
-	GenreIds    []uint `json:"genre_ids"`
-	CategoryIds []uint `json:"category_ids"`
+	MenuName       string `json:"menu_name"`
+	Description    string `json:"description"`
+	Price          *uint  `json:"price"`
+	Calories       *uint  `json:"calories"`
+	CookingMinutes *uint  `json:"cooking_minutes"`
+	ImageUrl       string `json:"image_url"`
+	GenreIds       []uint `json:"genre_ids"`
+	CategoryIds    []uint `json:"category_ids"`
 }
 
 type MenuPutResponse struct {
@@ -49,158 +75,494 @@ type MenuCategoryPatchRequest struct {
 	CategoryIds []uint `json:"category_ids"`
 }
 
+type MenuAllergenPatchRequest struct {
+	Allergens []string `json:"allergens"`
+}
+
+type MenuDietPatchRequest struct {
+	Diets []string `json:"diets"`
+}
+
+type RecipeIngredientRequest struct {
+	Name string `json:"name"`
+	// 1人前の分量
+	Quantity float64 `json:"quantity"`
+	Unit     string  `json:"unit"`
+	// 売り場（省略時はother）
+	Section string `json:"section"`
+}
+
+type RecipeStepRequest struct {
+	Instruction string `json:"instruction"`
+}
+
+type RecipePutRequest struct {
+	Ingredients []RecipeIngredientRequest `json:"ingredients"`
+	// 配列の並び順が手順の番号になる
+	Steps []RecipeStepRequest `json:"steps"`
+}
+
+type RecipeResponse struct {
+	Recipe domain.Recipe `json:"recipe"`
+}
+
 type MenuPatchResponse struct {
 	Menu domain.Menu `json:"menu"`
 }
 
+// menuExpand はexpandクエリで指定された、レスポンスに展開する関連データ
+type menuExpand struct {
+	genres     bool
+	categories bool
+}
+
+// parseMenuExpand はexpandクエリ（例: expand=genres,categories）を解析する
+func parseMenuExpand(c *gin.Context) (menuExpand, error) {
+	var expand menuExpand
+
+	for _, field := range strings.Split(c.Query("expand"), ",") {
+		switch strings.TrimSpace(field) {
+		case "":
+		case "genres":
+			expand.genres = true
+		case "categories":
+			expand.categories = true
+		default:
+			return menuExpand{}, fmt.Errorf("invalid expand: %s", field)
+		}
+	}
+
+	return expand, nil
+}
+
+// apply は展開指定されていない関連データをメニューから取り除く
+func (e menuExpand) apply(menu domain.Menu) domain.Menu {
+	if !e.genres {
+		menu.Genres = nil
+	}
+	if !e.categories {
+		menu.Categories = nil
+	}
+
+	return menu
+}
+
 func (h MenuHandler) GetAll(c *gin.Context) {
-	menus, err := h.menuUsecase.GetAll()
+	expand, err := parseMenuExpand(c)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
+	query, err := parseMenuQuery(c)
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
+	page, err := h.menuUsecase.FindMenus(query)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	for i, menu := range page.Menus {
+		page.Menus[i] = expand.apply(menu)
+	}
+
 	response := MenusGetResponse{
+		Menus: page.Menus,
+		Total: page.Total,
+	}
+	if page.Next != nil {
+		response.NextCursor = encodeMenuCursor(query.Sort, *page.Next)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMenu はメニューを1件取得する
+// If-None-MatchがETagに一致する場合は304を返す
+func (h MenuHandler) GetMenu(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
+	// パスパラメータからmenu_idを取得
+	menuId, err := strconv.Atoi(c.Param("menu_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
+		return
+	}
+
+	menu, err := h.menuUsecase.GetMenu(uint(menuId))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := MenuGetResponse{
+		Menu: expand.apply(menu),
+	}
+
+	respondWithETag(c, response)
+}
+
+// PickRandomMenus は条件に一致するメニューをランダムに選ぶ
+func (h MenuHandler) PickRandomMenus(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
+	query, err := parseRandomMenuQuery(c)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	menus, err := h.menuUsecase.PickRandomMenus(query)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	for i, menu := range menus {
+		menus[i] = expand.apply(menu)
+	}
+
+	response := MenusRandomResponse{
 		Menus: menus,
+		Seed:  query.Seed,
 	}
+
 	c.JSON(http.StatusOK, response)
 }
 
 func (h MenuHandler) CreateMenu(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
 	var req MenuPostRequest
 	// リクエストボディを取得
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "invalid request",
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
 		return
 	}
 
 	// メニューを作成
 	menu := domain.Menu{
-		MenuName:    req.MenuName,
-		GenreIds:    req.GenreIds,
-		CategoryIds: req.CategoryIds,
+		MenuName:       req.MenuName,
+		Description:    req.Description,
+		Price:          req.Price,
+		Calories:       req.Calories,
+		CookingMinutes: req.CookingMinutes,
+		ImageUrl:       req.ImageUrl,
+		GenreIds:       req.GenreIds,
+		CategoryIds:    req.CategoryIds,
 	}
 
 	createdMenu, err := h.menuUsecase.CreateMenu(menu)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
 	response := MenuPostResponse{
-		Menu: createdMenu,
+		Menu: expand.apply(createdMenu),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 func (h MenuHandler) UpdateMenu(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
 	var req MenuPutRequest
 	// リクエストボディを取得
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "invalid request",
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
 		return
 	}
 
 	// パスパラメータからmenu_idを取得
 	menuId, err := strconv.Atoi(c.Param("menu_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "invalid menu_id",
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
 		return
 	}
 
 	// メニューを更新
 	menu := domain.Menu{
-		MenuId:      uint(menuId),
-		MenuName:    req.MenuName,
-		GenreIds:    req.GenreIds,
-		CategoryIds: req.CategoryIds,
+		MenuId:         uint(menuId),
+		MenuName:       req.MenuName,
+		Description:    req.Description,
+		Price:          req.Price,
+		Calories:       req.Calories,
+		CookingMinutes: req.CookingMinutes,
+		ImageUrl:       req.ImageUrl,
+		GenreIds:       req.GenreIds,
+		CategoryIds:    req.CategoryIds,
 	}
 
 	updatedMenu, err := h.menuUsecase.UpdateMenu(menu)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
 	response := MenuPutResponse{
-		Menu: updatedMenu,
+		Menu: expand.apply(updatedMenu),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 func (h MenuHandler) UpdateGenreRelations(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
 	var req MenuGenrePatchRequest
 	// リクエストボディを取得
-	if err := c.BindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
 		return
 	}
 
 	// パスパラメータからmenu_idを取得
 	menuId, err := strconv.Atoi(c.Param("menu_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "invalid menu_id",
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
 		return
 	}
 
 	// ジャンルを更新
 	menu, err := h.menuUsecase.UpdateGenreRelations(uint(menuId), req.GenreIds)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
 	response := MenuPatchResponse{
-		Menu: menu,
+		Menu: expand.apply(menu),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
 func (h MenuHandler) UpdateCategoryRelations(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
 	var req MenuCategoryPatchRequest
 	// リクエストボディを取得
-	if err := c.BindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
 		return
 	}
 
 	// パスパラメータからmenu_idを取得
 	menuId, err := strconv.Atoi(c.Param("menu_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "invalid menu_id",
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
 		return
 	}
 
 	// カテゴリを更新
 	menu, err := h.menuUsecase.UpdateCategoryRelations(uint(menuId), req.CategoryIds)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
+		abortWithError(c, err)
+		return
+	}
+
+	response := MenuPatchResponse{
+		Menu: expand.apply(menu),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateAllergens はメニューに含まれるアレルゲンを置き換える
+func (h MenuHandler) UpdateAllergens(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
+	var req MenuAllergenPatchRequest
+	// リクエストボディを取得
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
+		return
+	}
+
+	// パスパラメータからmenu_idを取得
+	menuId, err := strconv.Atoi(c.Param("menu_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
+		return
+	}
+
+	// アレルゲンを更新
+	menu, err := h.menuUsecase.UpdateAllergens(uint(menuId), req.Allergens)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := MenuPatchResponse{
+		Menu: expand.apply(menu),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateDiets はメニューが対応する食事制限を置き換える
+func (h MenuHandler) UpdateDiets(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
+	var req MenuDietPatchRequest
+	// リクエストボディを取得
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
+		return
+	}
+
+	// パスパラメータからmenu_idを取得
+	menuId, err := strconv.Atoi(c.Param("menu_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
+		return
+	}
+
+	// 食事制限を更新
+	menu, err := h.menuUsecase.UpdateDiets(uint(menuId), req.Diets)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := MenuPatchResponse{
+		Menu: expand.apply(menu),
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRecipe はメニューの材料と調理手順を取得する
+// If-None-MatchがETagに一致する場合は304を返す
+func (h MenuHandler) GetRecipe(c *gin.Context) {
+	// パスパラメータからmenu_idを取得
+	menuId, err := strconv.Atoi(c.Param("menu_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
+		return
+	}
+
+	recipe, err := h.menuUsecase.GetRecipe(uint(menuId))
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := RecipeResponse{
+		Recipe: recipe,
+	}
+
+	respondWithETag(c, response)
+}
+
+// UpdateRecipe はメニューの材料と調理手順を置き換える
+func (h MenuHandler) UpdateRecipe(c *gin.Context) {
+	var req RecipePutRequest
+	// リクエストボディを取得
+	if err := c.ShouldBindJSON(&req); err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_request", "invalid request"))
+		return
+	}
+
+	// パスパラメータからmenu_idを取得
+	menuId, err := strconv.Atoi(c.Param("menu_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
+		return
+	}
+
+	// レシピを更新
+	recipe := domain.Recipe{
+		MenuId: uint(menuId),
+	}
+	for _, ingredient := range req.Ingredients {
+		recipe.Ingredients = append(recipe.Ingredients, domain.Ingredient{
+			Name:     ingredient.Name,
+			Quantity: ingredient.Quantity,
+			Unit:     ingredient.Unit,
+			Section:  ingredient.Section,
+		})
+	}
+	for _, step := range req.Steps {
+		recipe.Steps = append(recipe.Steps, domain.RecipeStep{
+			Instruction: step.Instruction,
 		})
+	}
+
+	updatedRecipe, err := h.menuUsecase.UpdateRecipe(recipe)
+	if err != nil {
+		abortWithError(c, err)
+		return
+	}
+
+	response := RecipeResponse{
+		Recipe: updatedRecipe,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreMenu は論理削除したメニューを復元する
+func (h MenuHandler) RestoreMenu(c *gin.Context) {
+	expand, err := parseMenuExpand(c)
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_expand", err.Error()))
+		return
+	}
+
+	// パスパラメータからmenu_idを取得
+	menuId, err := strconv.Atoi(c.Param("menu_id"))
+	if err != nil {
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
+		return
+	}
+
+	// メニューを復元
+	menu, err := h.menuUsecase.RestoreMenu(uint(menuId))
+	if err != nil {
+		abortWithError(c, err)
 		return
 	}
 
 	response := MenuPatchResponse{
-		Menu: menu,
+		Menu: expand.apply(menu),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -210,18 +572,14 @@ func (h MenuHandler) DeleteMenu(c *gin.Context) {
 	// パスパラメータからmenu_idを取得
 	menuId, err := strconv.Atoi(c.Param("menu_id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "invalid menu_id",
-		})
+		abortWithError(c, domain.NewBadRequest("invalid_menu_id", "invalid menu_id"))
 		return
 	}
 
 	// メニューを削除
 	err = h.menuUsecase.DeleteMenu(uint(menuId))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
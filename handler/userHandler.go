@@ -1,9 +1,9 @@
 package handler
 
 import (
+	"go-menu/domain"
 	"go-menu/resource/user"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,40 +18,27 @@ func ProvideUserHandler(userDriver user.UserDriver) *UserHandler {
 	return &UserHandler{userDriver: userDriver}
 }
 
-// CreateUserRequest ユーザー作成リクエスト
-type CreateUserRequest struct {
-	Auth0Sub string `json:"auth0Sub" binding:"required"`
-}
-
 // UserResponse ユーザーレスポンス
 type UserResponse struct {
 	User user.User `json:"user"`
 }
 
-// CreateUser ユーザーを作成または取得する
+// CreateUser 検証済みのトークンのユーザーを作成または取得する
+// 発行者とsubはトークンから取得し、email・nameはクレームから設定する
 func (h *UserHandler) CreateUser(c *gin.Context) {
-	var req CreateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "リクエストボディが無効です: " + err.Error(),
-		})
-		return
-	}
-
-	// Auth0 sub のフォーマットを簡単に検証
-	if strings.TrimSpace(req.Auth0Sub) == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"message": "auth0Sub は必須です",
-		})
+	issuer := c.GetString("issuer")
+	subject := c.GetString("auth0Sub")
+	if subject == "" {
+		abortWithError(c, domain.NewUnauthorized("unauthenticated", "user not authenticated"))
 		return
 	}
+	value, _ := c.Get("userProfile")
+	profile, _ := value.(user.UserProfile)
 
 	// ユーザーを作成または取得
-	userRecord, isNewUser, err := h.userDriver.CreateOrGetUser(req.Auth0Sub)
+	userRecord, isNewUser, err := h.userDriver.CreateOrGetUser(issuer, subject, profile)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"message": "ユーザーの作成または取得に失敗しました: " + err.Error(),
-		})
+		abortWithError(c, err)
 		return
 	}
 
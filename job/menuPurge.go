@@ -0,0 +1,61 @@
+package job
+
+import (
+	"go-menu/usecase"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// 論理削除したメニューを保持する日数の既定値
+	defaultMenuRetentionDays = 30
+	// 物理削除を実行する間隔
+	menuPurgeInterval = 24 * time.Hour
+)
+
+// MenuPurgeConfig 論理削除したメニューの物理削除ジョブの設定
+type MenuPurgeConfig struct {
+	// 論理削除してから物理削除するまでの期間
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+// NewMenuPurgeConfig 環境変数（MENU_RETENTION_DAYS）から設定を作成
+func NewMenuPurgeConfig() MenuPurgeConfig {
+	days := defaultMenuRetentionDays
+	if value := os.Getenv("MENU_RETENTION_DAYS"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			log.Println("MENU_RETENTION_DAYSが不正なため既定値を使用します: ", value)
+		} else {
+			days = n
+		}
+	}
+
+	return MenuPurgeConfig{
+		Retention: time.Duration(days) * 24 * time.Hour,
+		Interval:  menuPurgeInterval,
+	}
+}
+
+// StartMenuPurge 保持期間を過ぎた論理削除済みメニューを定期的に物理削除する
+// 起動時に1回実行し、以降は一定間隔で実行する。呼び出し元をブロックしない
+func StartMenuPurge(menuUsecase usecase.MenuUsecase, config MenuPurgeConfig) {
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		for {
+			purged, err := menuUsecase.PurgeDeletedMenus(config.Retention)
+			if err != nil {
+				log.Println("削除済みメニューの物理削除に失敗しました: ", err)
+			} else if purged > 0 {
+				log.Println("削除済みメニューを物理削除しました: ", purged)
+			}
+
+			<-ticker.C
+		}
+	}()
+}
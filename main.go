@@ -1,11 +1,16 @@
 package main
 
 import (
+	"go-menu/di"
+	"go-menu/job"
 	"go-menu/router"
 	"log"
 )
 
 func main() {
+	// 保持期間を過ぎた論理削除済みメニューを定期的に物理削除する
+	job.StartMenuPurge(di.InitMenuUsecase(), job.NewMenuPurgeConfig())
+
 	s := router.NewServer()
 	if err := s.Run(":8080"); err != nil {
 		log.Fatal("サーバーの起動に失敗しました: ", err)
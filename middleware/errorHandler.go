@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"errors"
+	"go-menu/domain"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemDetails RFC 7807 形式のエラーレスポンス
+type ProblemDetails struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// クライアントがエラーを判別するための安定したコード
+	Code   string              `json:"code"`
+	Errors []domain.FieldError `json:"errors,omitempty"`
+}
+
+// ErrorHandler ハンドラーが c.Error で登録したエラーを application/problem+json 形式で返すミドルウェア
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		// エラーがない場合や既にレスポンスを書き込んでいる場合は何もしない
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		problem := NewProblemDetails(c.Errors.Last().Err)
+		problem.Instance = c.Request.URL.Path
+
+		c.Header("Content-Type", "application/problem+json")
+		c.JSON(problem.Status, problem)
+	}
+}
+
+// NewProblemDetails エラーからRFC 7807形式のレスポンスを作成
+func NewProblemDetails(err error) ProblemDetails {
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == domain.KindInternal {
+		// 内部エラーの詳細はクライアントに返さない（ログにはgin.Loggerが出力する）
+		return ProblemDetails{
+			Type:   "about:blank",
+			Title:  http.StatusText(http.StatusInternalServerError),
+			Status: http.StatusInternalServerError,
+			Detail: "internal server error",
+			Code:   "internal_error",
+		}
+	}
+
+	status := statusOf(domainErr.Kind)
+	return ProblemDetails{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: domainErr.Message,
+		Code:   domainErr.Code,
+		Errors: domainErr.Fields,
+	}
+}
+
+// statusOf ドメインエラーの種類に対応するHTTPステータスコードを返す
+func statusOf(kind domain.ErrorKind) int {
+	switch kind {
+	case domain.KindBadRequest:
+		return http.StatusBadRequest
+	case domain.KindValidation:
+		return http.StatusUnprocessableEntity
+	case domain.KindNotFound:
+		return http.StatusNotFound
+	case domain.KindConflict:
+		return http.StatusConflict
+	case domain.KindForbidden:
+		return http.StatusForbidden
+	case domain.KindUnauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// abortWithError エラーを登録して後続の処理を中断する
+func abortWithError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
@@ -0,0 +1,350 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// 取得した鍵を使う期間の既定値
+	defaultJWKSTTL = time.Hour
+	// バックグラウンドで鍵を再取得する間隔の既定値
+	defaultJWKSRefreshInterval = 15 * time.Minute
+	// 未知のkidによる再取得の最短間隔の既定値
+	defaultJWKSMinRefetchInterval = time.Minute
+	// JWKS取得のタイムアウトの既定値
+	defaultJWKSTimeout = 5 * time.Second
+)
+
+// JWKSResponse JWKS レスポンス構造体
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK JSON Web Key 構造体
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg,omitempty"`
+	// RSA鍵
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// 楕円曲線鍵（EC: P-256、OKP: Ed25519）
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// openIDConfiguration OIDCディスカバリードキュメントのうち使用する項目
+type openIDConfiguration struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+// JWKSConfig JWKSキャッシュの設定
+type JWKSConfig struct {
+	// JWKSのURL（空の場合はIssuerのディスカバリードキュメントのjwks_uriを使う）
+	URL string
+	// ディスカバリードキュメントを取得する発行者
+	Issuer     string
+	HTTPClient *http.Client
+	// 取得した鍵を使う期間（再取得できない場合はこの期間を過ぎると鍵を使わない）
+	TTL time.Duration
+	// バックグラウンドで鍵を再取得する間隔
+	RefreshInterval time.Duration
+	// 未知のkidによる再取得の最短間隔（存在しないkidを大量に送るリクエストから取得元を守る）
+	MinRefetchInterval time.Duration
+}
+
+// JWKSCache kidごとに公開鍵をキャッシュする
+// 未知のkidの場合は再取得するが、同時に行う取得は1つに限定し、一定間隔より短い再取得は行わない
+type JWKSCache struct {
+	config JWKSConfig
+
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	expiresAt time.Time
+
+	// 取得を1つに限定するためのロック（lastFetchもこのロックで保護する）
+	fetchMu   sync.Mutex
+	lastFetch time.Time
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewJWKSCache JWKSキャッシュを作成（設定されていない項目は既定値を使う）
+func NewJWKSCache(config JWKSConfig) *JWKSCache {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultJWKSTimeout}
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultJWKSTTL
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaultJWKSRefreshInterval
+	}
+	if config.MinRefetchInterval <= 0 {
+		config.MinRefetchInterval = defaultJWKSMinRefetchInterval
+	}
+
+	return &JWKSCache{
+		config: config,
+		keys:   map[string]crypto.PublicKey{},
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start バックグラウンドでの鍵の取得を開始する
+// 起動時に1回取得し、以降は一定間隔で取得する。呼び出し元をブロックしない
+func (c *JWKSCache) Start() {
+	go func() {
+		ticker := time.NewTicker(c.config.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			if err := c.refresh(); err != nil {
+				log.Println("JWKSの取得に失敗しました: ", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-c.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop バックグラウンドでの鍵の取得を停止する
+func (c *JWKSCache) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+}
+
+// GetKey kidに対応する公開鍵を取得する
+func (c *JWKSCache) GetKey(kid string) (crypto.PublicKey, error) {
+	if key, ok := c.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	// 待っている間に他のリクエストが取得した場合はその結果を使う
+	if key, ok := c.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	// 直前に取得している場合は再取得しない
+	if !c.lastFetch.IsZero() && time.Since(c.lastFetch) < c.config.MinRefetchInterval {
+		return nil, fmt.Errorf("unable to find key for kid %q", kid)
+	}
+
+	if err := c.fetch(); err != nil {
+		return nil, err
+	}
+
+	if key, ok := c.cachedKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unable to find key for kid %q", kid)
+}
+
+// cachedKey 有効期間内のキャッシュからkidに対応する公開鍵を取得する
+func (c *JWKSCache) cachedKey(kid string) (crypto.PublicKey, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if time.Now().After(c.expiresAt) {
+		return nil, false
+	}
+
+	key, ok := c.keys[kid]
+	return key, ok
+}
+
+// refresh 他の取得と重ならないように鍵を取得する
+func (c *JWKSCache) refresh() error {
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+
+	return c.fetch()
+}
+
+// fetch 鍵を取得してキャッシュを置き換える（fetchMuを取得した状態で呼び出す）
+func (c *JWKSCache) fetch() error {
+	c.lastFetch = time.Now()
+
+	jwksURL, err := c.jwksURL()
+	if err != nil {
+		return err
+	}
+
+	var jwks JWKSResponse
+	if err := c.getJSON(jwksURL, &jwks); err != nil {
+		return err
+	}
+
+	// 署名検証に使わない鍵や変換できない鍵は無視する
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := convertJWKToPublicKey(jwk)
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	c.mu.Lock()
+	c.keys = keys
+	c.expiresAt = time.Now().Add(c.config.TTL)
+	c.mu.Unlock()
+
+	return nil
+}
+
+// jwksURL JWKSのURLを取得する（設定されていない場合はディスカバリードキュメントから取得する）
+func (c *JWKSCache) jwksURL() (string, error) {
+	if c.config.URL != "" {
+		return c.config.URL, nil
+	}
+
+	var discovery openIDConfiguration
+	if err := c.getJSON(discoveryURL(c.config.Issuer), &discovery); err != nil {
+		return "", err
+	}
+	if discovery.Issuer != c.config.Issuer {
+		return "", fmt.Errorf("issuer mismatch in discovery document: %q", discovery.Issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return "", errors.New("jwks_uri is missing in discovery document")
+	}
+
+	return discovery.JWKSURI, nil
+}
+
+// getJSON URLからJSONを取得する
+func (c *JWKSCache) getJSON(url string, v interface{}) error {
+	resp, err := c.config.HTTPClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status from %s: %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// discoveryURL 発行者のOIDCディスカバリードキュメントのURL
+func discoveryURL(issuer string) string {
+	return strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+}
+
+// convertJWKToPublicKey JWKを公開鍵に変換（RSA、EC P-256、Ed25519に対応）
+func convertJWKToPublicKey(jwk JWK) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		return convertJWKToRSAPublicKey(jwk)
+	case "EC":
+		return convertJWKToECDSAPublicKey(jwk)
+	case "OKP":
+		return convertJWKToEd25519PublicKey(jwk)
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", jwk.Kty)
+	}
+}
+
+// convertJWKToRSAPublicKey JWKをRSA公開鍵に変換
+func convertJWKToRSAPublicKey(jwk JWK) (*rsa.PublicKey, error) {
+	// n (modulus) をデコード
+	nBytes, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, err
+	}
+
+	// e (exponent) をデコード
+	eBytes, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, err
+	}
+
+	// big.Int に変換
+	n := big.NewInt(0).SetBytes(nBytes)
+
+	// exponent を int に変換
+	var e int
+	for i, b := range eBytes {
+		e += int(b) << (8 * (len(eBytes) - 1 - i))
+	}
+
+	return &rsa.PublicKey{
+		N: n,
+		E: e,
+	}, nil
+}
+
+// convertJWKToECDSAPublicKey JWKをECDSA公開鍵に変換（P-256のみ対応）
+func convertJWKToECDSAPublicKey(jwk JWK) (*ecdsa.PublicKey, error) {
+	if jwk.Crv != "P-256" {
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}
+	// 曲線上の点であることを確認する
+	if _, err := key.ECDH(); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// convertJWKToEd25519PublicKey JWKをEd25519公開鍵に変換
+func convertJWKToEd25519PublicKey(jwk JWK) (ed25519.PublicKey, error) {
+	if jwk.Crv != "Ed25519" {
+		return nil, fmt.Errorf("unsupported curve: %s", jwk.Crv)
+	}
+
+	xBytes, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	if err != nil {
+		return nil, err
+	}
+	if len(xBytes) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid Ed25519 public key size")
+	}
+
+	return ed25519.PublicKey(xBytes), nil
+}
@@ -0,0 +1,369 @@
+package middleware
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// jwksTestServer は鍵の一覧を差し替えられ、取得回数を数えるJWKSサーバー
+type jwksTestServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	keys   []JWK
+	status int
+	// レスポンスを返すまでの待ち時間（同時に届くリクエストを重ならせる）
+	delay time.Duration
+	// ディスカバリードキュメントで返す発行者（空の場合はサーバーのURL）
+	issuer string
+
+	jwksRequests      atomic.Int32
+	discoveryRequests atomic.Int32
+}
+
+func newJWKSTestServer(t *testing.T, keys ...JWK) *jwksTestServer {
+	t.Helper()
+
+	s := &jwksTestServer{keys: keys, status: http.StatusOK}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		s.discoveryRequests.Add(1)
+
+		s.mu.Lock()
+		issuer := s.issuer
+		s.mu.Unlock()
+		if issuer == "" {
+			issuer = s.URL
+		}
+
+		_ = json.NewEncoder(w).Encode(openIDConfiguration{Issuer: issuer, JWKSURI: s.URL + "/jwks.json"})
+	})
+	mux.HandleFunc("/jwks.json", func(w http.ResponseWriter, r *http.Request) {
+		s.jwksRequests.Add(1)
+
+		s.mu.Lock()
+		keys, status, delay := s.keys, s.status, s.delay
+		s.mu.Unlock()
+
+		time.Sleep(delay)
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(JWKSResponse{Keys: keys})
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *jwksTestServer) setKeys(keys ...JWK) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *jwksTestServer) setStatus(status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status = status
+}
+
+func (s *jwksTestServer) setDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = delay
+}
+
+func (s *jwksTestServer) setIssuer(issuer string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.issuer = issuer
+}
+
+// newEd25519JWK はテスト用のEd25519公開鍵のJWKを作成する
+func newEd25519JWK(t *testing.T, kid string) JWK {
+	t.Helper()
+
+	publicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	return JWK{Kty: "OKP", Use: "sig", Kid: kid, Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(publicKey)}
+}
+
+// newECJWK はテスト用のP-256公開鍵のJWKを作成する
+func newECJWK(t *testing.T, kid string) JWK {
+	t.Helper()
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	return JWK{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(privateKey.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(privateKey.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func TestJWKSCacheCachedKey(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	cache := NewJWKSCache(JWKSConfig{URL: server.URL + "/jwks.json"})
+
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	// キャッシュにあるkidは取得し直さない
+	for i := 0; i < 10; i++ {
+		if _, err := cache.GetKey("key-1"); err != nil {
+			t.Fatalf("GetKey() error = %v", err)
+		}
+	}
+
+	if got := server.jwksRequests.Load(); got != 1 {
+		t.Errorf("jwks requests = %d, want 1", got)
+	}
+}
+
+func TestJWKSCacheUnknownKidSingleFlight(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	cache := NewJWKSCache(JWKSConfig{URL: server.URL + "/jwks.json", MinRefetchInterval: time.Millisecond})
+
+	if err := cache.refresh(); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// 鍵のローテーション後、新しいkidのリクエストが同時に届く
+	server.setKeys(newEd25519JWK(t, "key-1"), newEd25519JWK(t, "key-2"))
+	server.setDelay(50 * time.Millisecond)
+
+	const concurrency = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, concurrency)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := cache.GetKey("key-2"); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Errorf("GetKey() error = %v", err)
+	}
+	// 起動時の1回と未知のkidによる1回
+	if got := server.jwksRequests.Load(); got != 2 {
+		t.Errorf("jwks requests = %d, want 2", got)
+	}
+}
+
+func TestJWKSCacheMinRefetchInterval(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	cache := NewJWKSCache(JWKSConfig{URL: server.URL + "/jwks.json", MinRefetchInterval: time.Hour})
+
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+
+	// 最短間隔内は未知のkidでも取得しない
+	for _, kid := range []string{"unknown-1", "unknown-2", "unknown-3"} {
+		if _, err := cache.GetKey(kid); err == nil {
+			t.Errorf("GetKey(%q) error = nil, want error", kid)
+		}
+	}
+
+	if got := server.jwksRequests.Load(); got != 1 {
+		t.Errorf("jwks requests = %d, want 1", got)
+	}
+}
+
+func TestJWKSCacheUnknownKidAfterInterval(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	cache := NewJWKSCache(JWKSConfig{URL: server.URL + "/jwks.json", MinRefetchInterval: 20 * time.Millisecond})
+
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if _, err := cache.GetKey("unknown"); err == nil {
+		t.Fatal("GetKey() error = nil, want error")
+	}
+
+	// 最短間隔を過ぎれば再び取得する
+	time.Sleep(30 * time.Millisecond)
+	if _, err := cache.GetKey("unknown"); err == nil {
+		t.Fatal("GetKey() error = nil, want error")
+	}
+
+	if got := server.jwksRequests.Load(); got != 2 {
+		t.Errorf("jwks requests = %d, want 2", got)
+	}
+}
+
+func TestJWKSCacheTTLExpiry(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	cache := NewJWKSCache(JWKSConfig{
+		URL:                server.URL + "/jwks.json",
+		TTL:                30 * time.Millisecond,
+		MinRefetchInterval: time.Millisecond,
+	})
+
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+
+	// 有効期間を過ぎた鍵は使わずに取得し直す
+	time.Sleep(50 * time.Millisecond)
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if got := server.jwksRequests.Load(); got != 2 {
+		t.Errorf("jwks requests = %d, want 2", got)
+	}
+
+	// 有効期間を過ぎて取得にも失敗した場合は古い鍵を使わない
+	server.setStatus(http.StatusServiceUnavailable)
+	time.Sleep(50 * time.Millisecond)
+	if _, err := cache.GetKey("key-1"); err == nil {
+		t.Error("GetKey() with expired keys error = nil, want error")
+	}
+}
+
+func TestJWKSCacheNon200(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	server.setStatus(http.StatusInternalServerError)
+	cache := NewJWKSCache(JWKSConfig{URL: server.URL + "/jwks.json", MinRefetchInterval: time.Millisecond})
+
+	if _, err := cache.GetKey("key-1"); err == nil {
+		t.Fatal("GetKey() error = nil, want error")
+	}
+
+	// 取得に失敗しても有効期間内の鍵は使い続ける
+	server.setStatus(http.StatusOK)
+	time.Sleep(5 * time.Millisecond)
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	server.setStatus(http.StatusBadGateway)
+	if err := cache.refresh(); err == nil {
+		t.Error("refresh() error = nil, want error")
+	}
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Errorf("GetKey() after failed refresh error = %v", err)
+	}
+}
+
+func TestJWKSCacheDiscovery(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	cache := NewJWKSCache(JWKSConfig{Issuer: server.URL})
+
+	if _, err := cache.GetKey("key-1"); err != nil {
+		t.Fatalf("GetKey() error = %v", err)
+	}
+	if got := server.discoveryRequests.Load(); got != 1 {
+		t.Errorf("discovery requests = %d, want 1", got)
+	}
+	if got := server.jwksRequests.Load(); got != 1 {
+		t.Errorf("jwks requests = %d, want 1", got)
+	}
+}
+
+func TestJWKSCacheDiscoveryIssuerMismatch(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	server.setIssuer("https://attacker.example.com/")
+	cache := NewJWKSCache(JWKSConfig{Issuer: server.URL})
+
+	if _, err := cache.GetKey("key-1"); err == nil {
+		t.Fatal("GetKey() error = nil, want error")
+	}
+	// 発行者が一致しないディスカバリードキュメントのjwks_uriは使わない
+	if got := server.jwksRequests.Load(); got != 0 {
+		t.Errorf("jwks requests = %d, want 0", got)
+	}
+}
+
+func TestJWKSCacheSkipsUnusableKeys(t *testing.T) {
+	encryptionKey := newEd25519JWK(t, "enc")
+	encryptionKey.Use = "enc"
+	noKid := newEd25519JWK(t, "")
+	badBase64 := newEd25519JWK(t, "bad-base64")
+	badBase64.X = "!!!"
+	shortKey := newEd25519JWK(t, "short")
+	shortKey.X = base64.RawURLEncoding.EncodeToString([]byte("short"))
+	offCurve := newECJWK(t, "off-curve")
+	offCurve.Y = base64.RawURLEncoding.EncodeToString(make([]byte, 32))
+	unsupportedCurve := newECJWK(t, "p384")
+	unsupportedCurve.Crv = "P-384"
+	unsupportedType := JWK{Kty: "oct", Kid: "symmetric"}
+
+	server := newJWKSTestServer(t,
+		encryptionKey,
+		noKid,
+		badBase64,
+		shortKey,
+		offCurve,
+		unsupportedCurve,
+		unsupportedType,
+		newEd25519JWK(t, "ed25519"),
+		newECJWK(t, "ec"),
+	)
+	cache := NewJWKSCache(JWKSConfig{URL: server.URL + "/jwks.json", MinRefetchInterval: time.Hour})
+
+	// 使える鍵だけが取得できる
+	for _, kid := range []string{"ed25519", "ec"} {
+		if _, err := cache.GetKey(kid); err != nil {
+			t.Errorf("GetKey(%q) error = %v", kid, err)
+		}
+	}
+	for _, kid := range []string{"enc", "bad-base64", "short", "off-curve", "p384", "symmetric"} {
+		if _, err := cache.GetKey(kid); err == nil {
+			t.Errorf("GetKey(%q) error = nil, want error", kid)
+		}
+	}
+
+	if got := server.jwksRequests.Load(); got != 1 {
+		t.Errorf("jwks requests = %d, want 1", got)
+	}
+}
+
+func TestJWKSCacheStartStop(t *testing.T) {
+	server := newJWKSTestServer(t, newEd25519JWK(t, "key-1"))
+	cache := NewJWKSCache(JWKSConfig{URL: server.URL + "/jwks.json", RefreshInterval: 10 * time.Millisecond})
+
+	// 起動時に取得し、以降は一定間隔で取得する
+	cache.Start()
+	time.Sleep(35 * time.Millisecond)
+	cache.Stop()
+	cache.Stop()
+
+	requests := server.jwksRequests.Load()
+	if requests < 2 {
+		t.Errorf("jwks requests = %d, want at least 2", requests)
+	}
+
+	// 停止後は取得しない
+	time.Sleep(30 * time.Millisecond)
+	if got := server.jwksRequests.Load(); got > requests+1 {
+		t.Errorf("jwks requests after Stop = %d, want at most %d", got, requests+1)
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"fmt"
+	"go-menu/usecase"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// カレンダー購読URLのパス（/v1/calendar/<token>.ics）
+	calendarFeedPrefix = "/v1/calendar/"
+	calendarFeedSuffix = ".ics"
+	// 発行済みの購読URL（/v1/calendar.ics?token=）のトークンのパラメーター
+	calendarTokenParam = "token"
+)
+
+// RequestLogger リクエストのログを出力するミドルウェア（gin.Loggerと同じ形式）
+// カレンダー購読トークンはURLに含まれるため、保存しているハッシュに置き換えて出力する
+func RequestLogger() gin.HandlerFunc {
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			param.Path = redactCalendarToken(param.Path)
+			return formatLog(param)
+		},
+	})
+}
+
+// redactCalendarToken パスとクエリに含まれるカレンダー購読トークンをハッシュに置き換える
+func redactCalendarToken(path string) string {
+	path, rawQuery, hasQuery := strings.Cut(path, "?")
+
+	if token, ok := strings.CutPrefix(path, calendarFeedPrefix); ok {
+		if token, ok := strings.CutSuffix(token, calendarFeedSuffix); ok && token != "" && !strings.Contains(token, "/") {
+			path = calendarFeedPrefix + redactedToken(token) + calendarFeedSuffix
+		}
+	}
+
+	if !hasQuery {
+		return path
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// 解析できないクエリはトークンを含む可能性があるため出力しない
+		return path + "?[invalid query]"
+	}
+	if tokens, ok := query[calendarTokenParam]; ok {
+		for i, token := range tokens {
+			tokens[i] = redactedToken(token)
+		}
+		rawQuery = query.Encode()
+	}
+
+	return path + "?" + rawQuery
+}
+
+// redactedToken ログに出力するトークンのハッシュ（calendar_tokensに保存している値）
+func redactedToken(token string) string {
+	return "sha256-" + usecase.HashCalendarToken(token)
+}
+
+// formatLog gin.Loggerの既定の形式でログを整形する
+func formatLog(param gin.LogFormatterParams) string {
+	var statusColor, methodColor, resetColor string
+	if param.IsOutputColor() {
+		statusColor = param.StatusCodeColor()
+		methodColor = param.MethodColor()
+		resetColor = param.ResetColor()
+	}
+
+	if param.Latency > time.Minute {
+		param.Latency = param.Latency.Truncate(time.Second)
+	}
+
+	return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+		param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+		statusColor, param.StatusCode, resetColor,
+		param.Latency,
+		param.ClientIP,
+		methodColor, param.Method, resetColor,
+		param.Path,
+		param.ErrorMessage,
+	)
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"bytes"
+	"go-menu/usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRedactCalendarToken(t *testing.T) {
+	hash := "sha256-" + usecase.HashCalendarToken("secret-token")
+
+	tests := []struct {
+		name string
+		path string
+		want string
+	}{
+		{
+			name: "購読URLのパス",
+			path: "/v1/calendar/secret-token.ics",
+			want: "/v1/calendar/" + hash + ".ics",
+		},
+		{
+			name: "発行済みの購読URLのクエリ",
+			path: "/v1/calendar.ics?token=secret-token",
+			want: "/v1/calendar.ics?token=" + hash,
+		},
+		{
+			name: "他のパラメーターは残す",
+			path: "/v1/calendar.ics?a=1&token=secret-token",
+			want: "/v1/calendar.ics?a=1&token=" + hash,
+		},
+		{
+			name: "トークン発行のエンドポイントはそのまま",
+			path: "/v1/calendar/token",
+			want: "/v1/calendar/token",
+		},
+		{
+			name: "トークンを含まないURLはそのまま",
+			path: "/v1/menus?limit=10",
+			want: "/v1/menus?limit=10",
+		},
+		{
+			name: "解析できないクエリは出力しない",
+			path: "/v1/calendar.ics?token=%zz",
+			want: "/v1/calendar.ics?[invalid query]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactCalendarToken(tt.path); got != tt.want {
+				t.Errorf("redactCalendarToken(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestLoggerRedactsCalendarToken(t *testing.T) {
+	var buf bytes.Buffer
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &buf
+	t.Cleanup(func() { gin.DefaultWriter = defaultWriter })
+
+	r := gin.New()
+	r.Use(RequestLogger())
+	r.GET("/v1/calendar/:feed", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/v1/calendar.ics", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/v1/calendar/secret-token.ics", "/v1/calendar.ics?token=secret-token"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	log := buf.String()
+	if strings.Contains(log, "secret-token") {
+		t.Errorf("log contains the calendar token: %s", log)
+	}
+	if got := strings.Count(log, usecase.HashCalendarToken("secret-token")); got != 2 {
+		t.Errorf("log contains the token hash %d times, want 2: %s", got, log)
+	}
+}
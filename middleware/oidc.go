@@ -0,0 +1,276 @@
+package middleware
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// exp・nbf・iatの検証で許容する時計のずれの既定値
+	defaultClockSkew = time.Minute
+)
+
+// 受け付ける署名アルゴリズム
+var supportedSigningMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodEdDSA.Alg(),
+}
+
+// IssuerConfig 信頼するトークン発行者の設定
+type IssuerConfig struct {
+	// ユーザーを区別するための発行者名（users.issuerに保存する）
+	Name string `json:"name"`
+	// iss クレームと一致する発行者のURL
+	Issuer   string `json:"issuer"`
+	Audience string `json:"audience"`
+	// JWKSのURL（省略時はOIDCディスカバリーで取得する）
+	JWKSURL string `json:"jwks_url,omitempty"`
+	// 権限（admin, editor, viewer）を含むクレーム名（文字列または配列、省略時はクレームの権限を使わない）
+	RolesClaim string `json:"roles_claim,omitempty"`
+}
+
+// AuthConfig 認証の設定情報
+type AuthConfig struct {
+	Issuers []IssuerConfig
+	// exp・nbf・iatの検証で許容する時計のずれ
+	ClockSkew  time.Duration
+	HTTPClient *http.Client
+	// trueの場合は未登録のユーザーを初回のリクエストで作成する
+	JITProvisioning bool
+}
+
+// NewAuthConfig 環境変数から認証設定を作成
+// AUTH0_DOMAIN・AUTH0_AUDIENCE・AUTH0_ROLES_CLAIM: Auth0（発行者名 auth0）
+// OIDC_ISSUERS: 追加で信頼する発行者（IssuerConfigのJSON配列）
+// AUTH_CLOCK_SKEW_SECONDS: 許容する時計のずれ（秒）
+// AUTH_JIT_PROVISIONING: trueの場合は未登録のユーザーを初回のリクエストで作成する
+func NewAuthConfig() AuthConfig {
+	jitProvisioning, _ := strconv.ParseBool(os.Getenv("AUTH_JIT_PROVISIONING"))
+	config := AuthConfig{
+		ClockSkew:       defaultClockSkew,
+		JITProvisioning: jitProvisioning,
+	}
+
+	if domain := os.Getenv("AUTH0_DOMAIN"); domain != "" {
+		config.Issuers = append(config.Issuers, IssuerConfig{
+			Name:       user.DefaultIssuer,
+			Issuer:     fmt.Sprintf("https://%s/", domain),
+			Audience:   os.Getenv("AUTH0_AUDIENCE"),
+			RolesClaim: os.Getenv("AUTH0_ROLES_CLAIM"),
+		})
+	}
+
+	if value := os.Getenv("OIDC_ISSUERS"); value != "" {
+		var issuers []IssuerConfig
+		if err := json.Unmarshal([]byte(value), &issuers); err != nil {
+			log.Fatal("OIDC_ISSUERSの形式が不正です: ", err)
+		}
+		config.Issuers = append(config.Issuers, issuers...)
+	}
+
+	if value := os.Getenv("AUTH_CLOCK_SKEW_SECONDS"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			log.Println("AUTH_CLOCK_SKEW_SECONDSが不正なため既定値を使用します: ", value)
+		} else {
+			config.ClockSkew = time.Duration(seconds) * time.Second
+		}
+	}
+
+	for _, issuer := range config.Issuers {
+		if issuer.Name == "" || issuer.Issuer == "" || issuer.Audience == "" {
+			log.Fatal("発行者の設定にはname、issuer、audienceが必要です: ", issuer.Issuer)
+		}
+	}
+
+	return config
+}
+
+// VerifiedToken 検証済みのトークン
+type VerifiedToken struct {
+	// 発行者名（IssuerConfig.Name）
+	Issuer  string
+	Subject string
+	Claims  jwt.MapClaims
+	// クレームに含まれる権限（発行者のRolesClaimが設定されている場合のみ）
+	Roles []string
+}
+
+// KeySet kidに対応する公開鍵を提供する
+type KeySet interface {
+	GetKey(kid string) (crypto.PublicKey, error)
+	// バックグラウンドでの鍵の取得を開始・停止する
+	Start()
+	Stop()
+}
+
+// Profile クレームからユーザーのプロフィールを取得する
+// emailはemail_verifiedがfalseの場合は使わない
+func (t VerifiedToken) Profile() user.UserProfile {
+	var profile user.UserProfile
+
+	if email, ok := t.Claims["email"].(string); ok {
+		if verified, ok := t.Claims["email_verified"].(bool); !ok || verified {
+			profile.Email = email
+		}
+	}
+	for _, claim := range []string{"name", "preferred_username", "nickname"} {
+		if name, ok := t.Claims[claim].(string); ok && name != "" {
+			profile.Name = name
+			break
+		}
+	}
+
+	return profile
+}
+
+// trustedIssuer 信頼する発行者と公開鍵
+type trustedIssuer struct {
+	config IssuerConfig
+	keySet KeySet
+}
+
+// TokenVerifier 複数の発行者のトークンを検証する
+type TokenVerifier struct {
+	// iss クレームの値ごとの発行者
+	issuers   map[string]trustedIssuer
+	clockSkew time.Duration
+}
+
+// NewTokenVerifier 認証設定からトークン検証を作成
+func NewTokenVerifier(config AuthConfig) *TokenVerifier {
+	verifier := &TokenVerifier{
+		issuers:   map[string]trustedIssuer{},
+		clockSkew: config.ClockSkew,
+	}
+
+	for _, issuer := range config.Issuers {
+		verifier.AddIssuer(issuer, NewJWKSCache(JWKSConfig{
+			URL:        issuer.JWKSURL,
+			Issuer:     issuer.Issuer,
+			HTTPClient: config.HTTPClient,
+		}))
+	}
+
+	return verifier
+}
+
+// AddIssuer 信頼する発行者を追加する（Startより前に呼び出す）
+func (v *TokenVerifier) AddIssuer(config IssuerConfig, keySet KeySet) {
+	v.issuers[config.Issuer] = trustedIssuer{
+		config: config,
+		keySet: keySet,
+	}
+}
+
+// Start 各発行者の公開鍵のバックグラウンドでの取得を開始する
+func (v *TokenVerifier) Start() {
+	for _, issuer := range v.issuers {
+		issuer.keySet.Start()
+	}
+}
+
+// Stop 各発行者の公開鍵のバックグラウンドでの取得を停止する
+func (v *TokenVerifier) Stop() {
+	for _, issuer := range v.issuers {
+		issuer.keySet.Stop()
+	}
+}
+
+// Verify トークンの署名とクレーム（iss, aud, exp, nbf, iat）を検証する
+func (v *TokenVerifier) Verify(tokenString string) (VerifiedToken, error) {
+	// 署名を検証する前に発行者を特定する
+	unverified, _, err := jwt.NewParser().ParseUnverified(tokenString, jwt.MapClaims{})
+	if err != nil {
+		return VerifiedToken{}, domain.NewUnauthorized("invalid_token", "invalid token: "+err.Error())
+	}
+	iss, err := unverified.Claims.GetIssuer()
+	if err != nil || iss == "" {
+		return VerifiedToken{}, domain.NewUnauthorized("invalid_issuer", "issuer claim is required")
+	}
+	issuer, ok := v.issuers[iss]
+	if !ok {
+		return VerifiedToken{}, domain.NewUnauthorized("invalid_issuer", "untrusted issuer")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, ok := token.Header["kid"].(string)
+		if !ok {
+			return nil, errors.New("kid header is required")
+		}
+
+		return issuer.keySet.GetKey(kid)
+	},
+		jwt.WithValidMethods(supportedSigningMethods),
+		jwt.WithIssuer(issuer.config.Issuer),
+		jwt.WithAudience(issuer.config.Audience),
+		jwt.WithLeeway(v.clockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return VerifiedToken{}, toAuthError(err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return VerifiedToken{}, domain.NewUnauthorized("invalid_token", "subject claim is required")
+	}
+
+	return VerifiedToken{
+		Issuer:  issuer.config.Name,
+		Subject: subject,
+		Claims:  claims,
+		Roles:   rolesFromClaim(claims[issuer.config.RolesClaim]),
+	}, nil
+}
+
+// rolesFromClaim クレームの値（文字列または配列）から既知の権限を取り出す
+func rolesFromClaim(value interface{}) []string {
+	var values []interface{}
+	switch v := value.(type) {
+	case string:
+		values = []interface{}{v}
+	case []interface{}:
+		values = v
+	}
+
+	roles := []string{}
+	for _, v := range values {
+		if role, ok := v.(string); ok && slices.Contains(domain.Roles, role) {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// toAuthError トークンの検証エラーを401のエラーに変換する
+func toAuthError(err error) error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return domain.NewUnauthorized("token_expired", "token has expired").Wrap(err)
+	case errors.Is(err, jwt.ErrTokenNotValidYet), errors.Is(err, jwt.ErrTokenUsedBeforeIssued):
+		return domain.NewUnauthorized("token_not_yet_valid", "token is not valid yet").Wrap(err)
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return domain.NewUnauthorized("invalid_audience", "invalid audience").Wrap(err)
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return domain.NewUnauthorized("invalid_issuer", "invalid issuer").Wrap(err)
+	default:
+		return domain.NewUnauthorized("invalid_token", "invalid token: "+err.Error()).Wrap(err)
+	}
+}
@@ -0,0 +1,274 @@
+package middleware
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"go-menu/domain"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testAudience   = "go-menu-test"
+	testRolesClaim = "https://go-menu.example.com/roles"
+)
+
+// testSigningKey はテスト用のEd25519の署名鍵と、対応する公開鍵のJWK
+type testSigningKey struct {
+	privateKey ed25519.PrivateKey
+	jwk        JWK
+}
+
+func newTestSigningKey(t *testing.T, kid string) testSigningKey {
+	t.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+
+	return testSigningKey{
+		privateKey: privateKey,
+		jwk:        JWK{Kty: "OKP", Use: "sig", Kid: kid, Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(publicKey)},
+	}
+}
+
+// sign はクレームに署名したトークンを作成する（kidが空の場合はヘッダーに含めない）
+func (k testSigningKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	if k.jwk.Kid != "" {
+		token.Header["kid"] = k.jwk.Kid
+	}
+	signed, err := token.SignedString(k.privateKey)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	return signed
+}
+
+// newTestVerifier はJWKSテストサーバーを発行者として信頼するTokenVerifierを作成する
+func newTestVerifier(t *testing.T, clockSkew time.Duration, keys ...JWK) (*TokenVerifier, *jwksTestServer) {
+	t.Helper()
+
+	server := newJWKSTestServer(t, keys...)
+	verifier := NewTokenVerifier(AuthConfig{
+		Issuers: []IssuerConfig{{
+			Name:       "test",
+			Issuer:     server.URL,
+			Audience:   testAudience,
+			JWKSURL:    server.URL + "/jwks.json",
+			RolesClaim: testRolesClaim,
+		}},
+		ClockSkew: clockSkew,
+	})
+
+	return verifier, server
+}
+
+// validClaims はテストサーバーが発行した有効なトークンのクレーム
+func validClaims(issuer string, now time.Time) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss": issuer,
+		"sub": "user-1",
+		"aud": testAudience,
+		"iat": now.Unix(),
+		"nbf": now.Unix(),
+		"exp": now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestTokenVerifierVerify(t *testing.T) {
+	key := newTestSigningKey(t, "key-1")
+	noKid := newTestSigningKey(t, "")
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		clockSkew time.Duration
+		// 有効なクレームを書き換える
+		modify func(claims jwt.MapClaims)
+		// 署名方法を変える場合に指定する（省略時はkeyで署名する）
+		sign func(t *testing.T, claims jwt.MapClaims) string
+		// 期待するエラーコード（空の場合は成功）
+		wantCode  string
+		wantRoles []string
+	}{
+		{
+			name:      "有効なトークン",
+			wantRoles: []string{},
+		},
+		{
+			name:     "信頼しない発行者",
+			modify:   func(claims jwt.MapClaims) { claims["iss"] = "https://attacker.example.com/" },
+			wantCode: "invalid_issuer",
+		},
+		{
+			name:     "発行者がない",
+			modify:   func(claims jwt.MapClaims) { delete(claims, "iss") },
+			wantCode: "invalid_issuer",
+		},
+		{
+			name:     "対象外のaudience",
+			modify:   func(claims jwt.MapClaims) { claims["aud"] = "another-api" },
+			wantCode: "invalid_audience",
+		},
+		{
+			name: "対応していない署名アルゴリズム",
+			sign: func(t *testing.T, claims jwt.MapClaims) string {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+				token.Header["kid"] = "key-1"
+				signed, err := token.SignedString([]byte("shared-secret"))
+				if err != nil {
+					t.Fatalf("SignedString() error = %v", err)
+				}
+				return signed
+			},
+			wantCode: "invalid_token",
+		},
+		{
+			name:     "署名していないトークン",
+			sign:     unsignedToken,
+			wantCode: "invalid_token",
+		},
+		{
+			name:     "有効期限切れ",
+			modify:   func(claims jwt.MapClaims) { claims["exp"] = now.Add(-30 * time.Second).Unix() },
+			wantCode: "token_expired",
+		},
+		{
+			name:      "許容する時計のずれの範囲内の有効期限切れ",
+			clockSkew: time.Minute,
+			modify:    func(claims jwt.MapClaims) { claims["exp"] = now.Add(-30 * time.Second).Unix() },
+			wantRoles: []string{},
+		},
+		{
+			name:      "許容する時計のずれを超えた有効期限切れ",
+			clockSkew: time.Minute,
+			modify:    func(claims jwt.MapClaims) { claims["exp"] = now.Add(-2 * time.Minute).Unix() },
+			wantCode:  "token_expired",
+		},
+		{
+			name:     "有効期限がない",
+			modify:   func(claims jwt.MapClaims) { delete(claims, "exp") },
+			wantCode: "invalid_token",
+		},
+		{
+			name:     "有効になる前のトークン",
+			modify:   func(claims jwt.MapClaims) { claims["nbf"] = now.Add(30 * time.Second).Unix() },
+			wantCode: "token_not_yet_valid",
+		},
+		{
+			name:      "許容する時計のずれの範囲内のnbf",
+			clockSkew: time.Minute,
+			modify:    func(claims jwt.MapClaims) { claims["nbf"] = now.Add(30 * time.Second).Unix() },
+			wantRoles: []string{},
+		},
+		{
+			name:      "許容する時計のずれを超えたnbf",
+			clockSkew: time.Minute,
+			modify:    func(claims jwt.MapClaims) { claims["nbf"] = now.Add(2 * time.Minute).Unix() },
+			wantCode:  "token_not_yet_valid",
+		},
+		{
+			name:     "未来に発行されたトークン",
+			modify:   func(claims jwt.MapClaims) { claims["iat"] = now.Add(30 * time.Second).Unix() },
+			wantCode: "token_not_yet_valid",
+		},
+		{
+			name:     "kidがない",
+			sign:     noKid.sign,
+			wantCode: "invalid_token",
+		},
+		{
+			name: "未知のkid",
+			sign: func(t *testing.T, claims jwt.MapClaims) string {
+				return newTestSigningKey(t, "unknown").sign(t, claims)
+			},
+			wantCode: "invalid_token",
+		},
+		{
+			name:     "subがない",
+			modify:   func(claims jwt.MapClaims) { delete(claims, "sub") },
+			wantCode: "invalid_token",
+		},
+		{
+			name:     "subが空",
+			modify:   func(claims jwt.MapClaims) { claims["sub"] = "" },
+			wantCode: "invalid_token",
+		},
+		{
+			name:      "権限のクレーム（文字列）",
+			modify:    func(claims jwt.MapClaims) { claims[testRolesClaim] = domain.RoleEditor },
+			wantRoles: []string{domain.RoleEditor},
+		},
+		{
+			name:      "権限のクレーム（配列、未知の権限は無視する）",
+			modify:    func(claims jwt.MapClaims) { claims[testRolesClaim] = []interface{}{"owner", domain.RoleAdmin, 1} },
+			wantRoles: []string{domain.RoleAdmin},
+		},
+		{
+			name:      "設定していないクレーム名の権限は使わない",
+			modify:    func(claims jwt.MapClaims) { claims["roles"] = domain.RoleAdmin },
+			wantRoles: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, server := newTestVerifier(t, tt.clockSkew, key.jwk)
+
+			claims := validClaims(server.URL, now)
+			if tt.modify != nil {
+				tt.modify(claims)
+			}
+			sign := key.sign
+			if tt.sign != nil {
+				sign = tt.sign
+			}
+
+			token, err := verifier.Verify(sign(t, claims))
+			if tt.wantCode != "" {
+				if err == nil {
+					t.Fatalf("Verify() error = nil, want %s", tt.wantCode)
+				}
+				problem := NewProblemDetails(err)
+				if problem.Status != http.StatusUnauthorized || problem.Code != tt.wantCode {
+					t.Errorf("Verify() error = %d %s, want 401 %s (%v)", problem.Status, problem.Code, tt.wantCode, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if token.Issuer != "test" || token.Subject != "user-1" {
+				t.Errorf("Verify() = %s/%s, want test/user-1", token.Issuer, token.Subject)
+			}
+			if !reflect.DeepEqual(token.Roles, tt.wantRoles) {
+				t.Errorf("Verify() roles = %v, want %v", token.Roles, tt.wantRoles)
+			}
+		})
+	}
+}
+
+// unsignedToken はalg=noneのトークンを作成する
+func unsignedToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, claims)
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	return signed
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"go-menu/domain"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole 指定した権限のいずれかを持つユーザー以外のリクエストを拒否するミドルウェア
+// 認証ミドルウェアの後に使用する
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, c.GetString("role")) {
+			abortWithError(c, domain.NewForbidden("insufficient_role", "this operation requires one of the roles: "+strings.Join(roles, ", ")))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// strongestRole 権限のうち最も強いものを返す（既知の権限がない場合はviewer）
+func strongestRole(roles []string) string {
+	for _, role := range domain.Roles {
+		if slices.Contains(roles, role) {
+			return role
+		}
+	}
+
+	return domain.RoleViewer
+}
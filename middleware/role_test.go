@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"encoding/json"
+	"go-menu/domain"
+	"go-menu/resource/user"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubUserDriver はGetUserBySubjectだけを固定の権限のユーザーで返すUserDriver
+type stubUserDriver struct {
+	user.UserDriver
+	role string
+}
+
+func (d stubUserDriver) GetUserBySubject(issuer, subject string) (user.User, error) {
+	return user.User{UserID: 1, Issuer: issuer, Auth0Sub: subject, Role: d.role}, nil
+}
+
+// newRoleTestRouter は認証と権限の確認を行うエンドポイント（/admin）と、コンテキストの権限を返すエンドポイント（/role）を作成する
+func newRoleTestRouter(verifier *TokenVerifier, dbRole string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(ErrorHandler())
+	auth := AuthMiddleware(stubUserDriver{role: dbRole}, verifier, AuthConfig{})
+	r.GET("/role", auth, func(c *gin.Context) { c.String(http.StatusOK, c.GetString("role")) })
+	r.GET("/admin", auth, RequireRole(domain.RoleAdmin, domain.RoleEditor), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	// 認証ミドルウェアを通さない場合は権限がない
+	r.GET("/unauthenticated", RequireRole(domain.RoleAdmin), func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	return r
+}
+
+// tokenWithRoles はクレームに権限を含むトークンを作成する（rolesがnilの場合はクレームを含めない）
+func tokenWithRoles(t *testing.T, key testSigningKey, issuer string, roles []interface{}) string {
+	t.Helper()
+
+	claims := validClaims(issuer, time.Now())
+	if roles != nil {
+		claims[testRolesClaim] = roles
+	}
+
+	return key.sign(t, claims)
+}
+
+func TestAuthMiddlewareRolePrecedence(t *testing.T) {
+	key := newTestSigningKey(t, "key-1")
+	verifier, server := newTestVerifier(t, 0, key.jwk)
+
+	tests := []struct {
+		name       string
+		dbRole     string
+		tokenRoles []interface{}
+		want       string
+	}{
+		{name: "クレームがない場合はusers.role", dbRole: domain.RoleEditor, want: domain.RoleEditor},
+		{name: "クレームの方が強い", dbRole: domain.RoleViewer, tokenRoles: []interface{}{domain.RoleAdmin}, want: domain.RoleAdmin},
+		{name: "users.roleの方が強い", dbRole: domain.RoleAdmin, tokenRoles: []interface{}{domain.RoleViewer}, want: domain.RoleAdmin},
+		{name: "クレームの複数の権限のうち最も強いもの", dbRole: domain.RoleViewer, tokenRoles: []interface{}{domain.RoleViewer, domain.RoleEditor}, want: domain.RoleEditor},
+		{name: "未知の権限は無視する", dbRole: domain.RoleViewer, tokenRoles: []interface{}{"owner"}, want: domain.RoleViewer},
+		{name: "既知の権限がない場合はviewer", dbRole: "", want: domain.RoleViewer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRoleTestRouter(verifier, tt.dbRole)
+
+			req := httptest.NewRequest(http.MethodGet, "/role", nil)
+			req.Header.Set("Authorization", "Bearer "+tokenWithRoles(t, key, server.URL, tt.tokenRoles))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+			}
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("role = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	key := newTestSigningKey(t, "key-1")
+	verifier, server := newTestVerifier(t, 0, key.jwk)
+	expired := validClaims(server.URL, time.Now().Add(-2*time.Hour))
+
+	tests := []struct {
+		name   string
+		path   string
+		dbRole string
+		// Authorizationヘッダー（空の場合は送らない）
+		authorization string
+		wantStatus    int
+		wantCode      string
+	}{
+		{
+			name:       "Authorizationヘッダーがない",
+			path:       "/admin",
+			dbRole:     domain.RoleAdmin,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   "authorization_required",
+		},
+		{
+			name:          "期限切れのトークン",
+			path:          "/admin",
+			dbRole:        domain.RoleAdmin,
+			authorization: "Bearer " + key.sign(t, expired),
+			wantStatus:    http.StatusUnauthorized,
+			wantCode:      "token_expired",
+		},
+		{
+			name:          "権限が足りない",
+			path:          "/admin",
+			dbRole:        domain.RoleViewer,
+			authorization: "Bearer " + tokenWithRoles(t, key, server.URL, nil),
+			wantStatus:    http.StatusForbidden,
+			wantCode:      "insufficient_role",
+		},
+		{
+			name:          "クレームの権限も足りない",
+			path:          "/admin",
+			dbRole:        domain.RoleViewer,
+			authorization: "Bearer " + tokenWithRoles(t, key, server.URL, []interface{}{domain.RoleViewer}),
+			wantStatus:    http.StatusForbidden,
+			wantCode:      "insufficient_role",
+		},
+		{
+			name:       "認証されていないリクエスト",
+			path:       "/unauthenticated",
+			wantStatus: http.StatusForbidden,
+			wantCode:   "insufficient_role",
+		},
+		{
+			name:          "users.roleで許可する",
+			path:          "/admin",
+			dbRole:        domain.RoleEditor,
+			authorization: "Bearer " + tokenWithRoles(t, key, server.URL, nil),
+			wantStatus:    http.StatusNoContent,
+		},
+		{
+			name:          "クレームの権限で許可する",
+			path:          "/admin",
+			dbRole:        domain.RoleViewer,
+			authorization: "Bearer " + tokenWithRoles(t, key, server.URL, []interface{}{domain.RoleAdmin}),
+			wantStatus:    http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRoleTestRouter(verifier, tt.dbRole)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+
+			if got := w.Header().Get("Content-Type"); got != "application/problem+json" {
+				t.Errorf("Content-Type = %q, want application/problem+json", got)
+			}
+			var problem ProblemDetails
+			if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("invalid problem+json %q: %v", w.Body.String(), err)
+			}
+			if problem.Status != tt.wantStatus || problem.Code != tt.wantCode || problem.Instance != tt.path {
+				t.Errorf("problem = %+v, want status %d, code %s, instance %s", problem, tt.wantStatus, tt.wantCode, tt.path)
+			}
+		})
+	}
+}
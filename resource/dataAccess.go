@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
 
+	"go-menu/domain"
+	"go-menu/resource/menu"
 	"go-menu/resource/user"
 
 	"gorm.io/driver/mysql"
@@ -27,18 +31,144 @@ func ConnectToDatabase() *gorm.DB {
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
 		// true:テーブル作成時に外部キー参照制約を無効化にすることで、マイグレーションエラーを防止
 		DisableForeignKeyConstraintWhenMigrating: false,
+		// 一意制約・外部キー制約の違反をgorm.ErrDuplicatedKeyなどに変換する
+		TranslateError: true,
 	})
 	if err != nil {
 		log.Fatal("データベース接続に失敗しました: ", err)
 	}
 
+	// 制約を追加する前に、制約に違反する既存のお気に入りを削除する
+	err = cleanUpFavorites(db)
+	if err != nil {
+		log.Fatal("マイグレーションに失敗しました: ", err)
+	}
+
 	// AutoMigrate実行
-	err = db.AutoMigrate(&user.User{}, &user.Favorite{})
+	err = db.AutoMigrate(&user.User{}, &user.Favorite{}, &user.MealHistory{}, &user.Suggestion{}, &user.MealPlan{}, &user.CalendarToken{},
+		&user.UserAllergen{}, &user.UserDiet{}, &menu.MenuAllergen{}, &menu.MenuDiet{},
+		&menu.MenuIngredient{}, &menu.MenuRecipeStep{})
+	if err != nil {
+		log.Fatal("マイグレーションに失敗しました: ", err)
+	}
+
+	// auth0_subは発行者ごとに一意にするため、auth0_subのみのユニークインデックスを削除する
+	if db.Migrator().HasIndex(&user.User{}, user.LegacySubjectIndex) {
+		err = db.Migrator().DropIndex(&user.User{}, user.LegacySubjectIndex)
+		if err != nil {
+			log.Fatal("マイグレーションに失敗しました: ", err)
+		}
+	}
+
+	// 権限を導入する前の管理者の設定（ADMIN_AUTH0_SUBS）をusers.roleに移行する
+	err = migrateLegacyAdmins(db)
+	if err != nil {
+		log.Fatal("マイグレーションに失敗しました: ", err)
+	}
+
+	// menu_listは既存のテーブルのため、追加した列のみマイグレーションする
+	err = addMissingColumns(db, &menu.Menu{}, append(menu.MenuAttributeColumns, menu.MenuSoftDeleteColumns...)...)
+	if err != nil {
+		log.Fatal("マイグレーションに失敗しました: ", err)
+	}
+	err = addMissingIndexes(db, &menu.Menu{}, menu.MenuSoftDeleteColumns...)
 	if err != nil {
 		log.Fatal("マイグレーションに失敗しました: ", err)
 	}
+	if !db.Migrator().HasConstraint(&user.Favorite{}, user.FavoriteMenuConstraint) {
+		err = db.Migrator().CreateConstraint(&user.Favorite{}, user.FavoriteMenuConstraint)
+		if err != nil {
+			log.Fatal("マイグレーションに失敗しました: ", err)
+		}
+	}
 
 	// データベース接続確認
 	log.Println("データベース接続に成功しました:", db)
 	return db
 }
+
+// addMissingColumns はテーブルに存在しない列のみを追加する
+// 既存の列の型は変更しない
+func addMissingColumns(db *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if db.Migrator().HasColumn(model, field) {
+			continue
+		}
+		if err := db.Migrator().AddColumn(model, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cleanUpFavorites は存在しないメニューを参照するお気に入りと、重複したお気に入り（古いものを残す）を削除する
+// 外部キー制約・ユニークインデックスを作成した後は違反するデータが存在しないため、作成前のみ実行する
+func cleanUpFavorites(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&user.Favorite{}) {
+		return nil
+	}
+
+	if !db.Migrator().HasConstraint(&user.Favorite{}, user.FavoriteMenuConstraint) {
+		result := db.Exec("DELETE FROM favorites WHERE NOT EXISTS (SELECT 1 FROM menu_list WHERE menu_list.menu_id = favorites.menu_id)")
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Println("存在しないメニューを参照するお気に入りを削除しました:", result.RowsAffected)
+	}
+
+	if !db.Migrator().HasIndex(&user.Favorite{}, user.FavoriteUniqueIndex) {
+		result := db.Exec("DELETE f FROM favorites AS f JOIN favorites AS older " +
+			"ON older.user_id = f.user_id AND older.menu_id = f.menu_id AND older.favorite_id < f.favorite_id")
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Println("重複したお気に入りを削除しました:", result.RowsAffected)
+	}
+
+	return nil
+}
+
+// migrateLegacyAdmins は廃止したADMIN_AUTH0_SUBS（カンマ区切りのAuth0のsub）のユーザーの権限をadminにする
+// 未登録のユーザーは移行できないため、登録後に再起動するかusers.roleを直接更新する
+func migrateLegacyAdmins(db *gorm.DB) error {
+	subjects := []string{}
+	for _, sub := range strings.Split(os.Getenv("ADMIN_AUTH0_SUBS"), ",") {
+		if sub = strings.TrimSpace(sub); sub != "" && !slices.Contains(subjects, sub) {
+			subjects = append(subjects, sub)
+		}
+	}
+	if len(subjects) == 0 {
+		return nil
+	}
+
+	result := db.Model(&user.User{}).
+		Where("issuer = ? AND auth0_sub IN ? AND role <> ?", user.DefaultIssuer, subjects, domain.RoleAdmin).
+		Update("role", domain.RoleAdmin)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	var registered int64
+	err := db.Model(&user.User{}).Where("issuer = ? AND auth0_sub IN ?", user.DefaultIssuer, subjects).Count(&registered).Error
+	if err != nil {
+		return err
+	}
+
+	log.Printf("ADMIN_AUTH0_SUBSは廃止しました。%d人の権限をadminに移行しました（未登録: %d人）。移行後は環境変数を削除してください", result.RowsAffected, int64(len(subjects))-registered)
+	return nil
+}
+
+// addMissingIndexes はテーブルに存在しないインデックスのみを作成する
+func addMissingIndexes(db *gorm.DB, model interface{}, fields ...string) error {
+	for _, field := range fields {
+		if db.Migrator().HasIndex(model, field) {
+			continue
+		}
+		if err := db.Migrator().CreateIndex(model, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
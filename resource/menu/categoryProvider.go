@@ -0,0 +1,120 @@
+package menu
+
+import (
+	"gorm.io/gorm"
+)
+
+type CategoryDriver interface {
+	GetAll() ([]Category, error)
+	GetByIds(categoryIds []uint) ([]Category, error)
+	CreateCategory(categoryName string) (Category, error)
+	UpdateCategory(categoryId uint, categoryName string) (Category, error)
+	DeleteCategory(categoryId uint, cascade bool) error
+}
+
+type CategoryDriverImpl struct {
+	conn *gorm.DB
+}
+
+func ProvideCategoryDriver(conn *gorm.DB) CategoryDriver {
+	return CategoryDriverImpl{conn: conn}
+}
+
+func (t CategoryDriverImpl) GetAll() ([]Category, error) {
+	categories := []Category{}
+	if err := t.conn.Order("category_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// GetByIds は指定したIDのカテゴリを取得する
+func (t CategoryDriverImpl) GetByIds(categoryIds []uint) ([]Category, error) {
+	categories := []Category{}
+	if len(categoryIds) == 0 {
+		return categories, nil
+	}
+	if err := t.conn.Where("category_id IN ?", categoryIds).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// CreateCategory はカテゴリを作成する
+func (t CategoryDriverImpl) CreateCategory(categoryName string) (Category, error) {
+	category := Category{CategoryName: categoryName}
+	if err := t.conn.Create(&category).Error; err != nil {
+		return Category{}, err
+	}
+
+	return category, nil
+}
+
+// UpdateCategory はカテゴリ名を更新する
+func (t CategoryDriverImpl) UpdateCategory(categoryId uint, categoryName string) (Category, error) {
+	var category Category
+
+	// カテゴリを取得
+	if err := t.conn.First(&category, categoryId).Error; err != nil {
+		return Category{}, err
+	}
+
+	// カテゴリを更新
+	if err := t.conn.Model(&category).Updates(Category{CategoryName: categoryName}).Error; err != nil {
+		return Category{}, err
+	}
+
+	return category, nil
+}
+
+// DeleteCategory はカテゴリを削除する
+// cascadeがfalseの場合、メニューから参照されていればErrReferencedを返す
+func (t CategoryDriverImpl) DeleteCategory(categoryId uint, cascade bool) error {
+	// トランザクション開始
+	tx := t.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// カテゴリの存在確認
+	var category Category
+	if err := tx.First(&category, categoryId).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 中間テーブルからの参照を確認
+	var count int64
+	if err := tx.Table("menu_category_relation").Where("category_id = ?", categoryId).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count > 0 {
+		if !cascade {
+			tx.Rollback()
+			return ErrReferenced
+		}
+		// 中間テーブルのデータを削除
+		if err := tx.Exec("DELETE FROM menu_category_relation WHERE category_id = ?", categoryId).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// カテゴリを削除
+	if err := tx.Delete(&category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package menu
+
+// MenuAllergen はメニューに含まれるアレルゲン
+type MenuAllergen struct {
+	MenuId   uint   `gorm:"primaryKey;column:menu_id;autoIncrement:false" json:"menu_id"`
+	Allergen string `gorm:"primaryKey;size:32;column:allergen" json:"allergen"`
+}
+
+func (MenuAllergen) TableName() string {
+	return "menu_allergen_relation"
+}
+
+// MenuDiet はメニューが対応する食事制限（ベジタリアン、ハラールなど）
+type MenuDiet struct {
+	MenuId uint   `gorm:"primaryKey;column:menu_id;autoIncrement:false" json:"menu_id"`
+	Diet   string `gorm:"primaryKey;size:32;column:diet" json:"diet"`
+}
+
+func (MenuDiet) TableName() string {
+	return "menu_diet_relation"
+}
+
+// UpdateAllergens はメニューに含まれるアレルゲンを置き換える
+func (t MenuDriverImpl) UpdateAllergens(menuId uint, allergens []string) (Menu, error) {
+	// メニューの存在確認
+	if _, err := t.GetMenu(menuId); err != nil {
+		return Menu{}, err
+	}
+
+	// トランザクション開始
+	tx := t.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 既存のアレルゲンを削除
+	if err := tx.Where("menu_id = ?", menuId).Delete(&MenuAllergen{}).Error; err != nil {
+		tx.Rollback()
+		return Menu{}, err
+	}
+
+	// アレルゲンを追加
+	if len(allergens) > 0 {
+		rows := []MenuAllergen{}
+		for _, allergen := range allergens {
+			rows = append(rows, MenuAllergen{MenuId: menuId, Allergen: allergen})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return Menu{}, err
+		}
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return Menu{}, err
+	}
+
+	return t.GetMenu(menuId)
+}
+
+// UpdateDiets はメニューが対応する食事制限を置き換える
+func (t MenuDriverImpl) UpdateDiets(menuId uint, diets []string) (Menu, error) {
+	// メニューの存在確認
+	if _, err := t.GetMenu(menuId); err != nil {
+		return Menu{}, err
+	}
+
+	// トランザクション開始
+	tx := t.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 既存の食事制限を削除
+	if err := tx.Where("menu_id = ?", menuId).Delete(&MenuDiet{}).Error; err != nil {
+		tx.Rollback()
+		return Menu{}, err
+	}
+
+	// 食事制限を追加
+	if len(diets) > 0 {
+		rows := []MenuDiet{}
+		for _, diet := range diets {
+			rows = append(rows, MenuDiet{MenuId: menuId, Diet: diet})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return Menu{}, err
+		}
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return Menu{}, err
+	}
+
+	return t.GetMenu(menuId)
+}
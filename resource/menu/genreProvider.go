@@ -0,0 +1,125 @@
+package menu
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrReferenced はメニューから参照されているため削除できないことを表す
+var ErrReferenced = errors.New("referenced by menus")
+
+type GenreDriver interface {
+	GetAll() ([]Genre, error)
+	GetByIds(genreIds []uint) ([]Genre, error)
+	CreateGenre(genreName string) (Genre, error)
+	UpdateGenre(genreId uint, genreName string) (Genre, error)
+	DeleteGenre(genreId uint, cascade bool) error
+}
+
+type GenreDriverImpl struct {
+	conn *gorm.DB
+}
+
+func ProvideGenreDriver(conn *gorm.DB) GenreDriver {
+	return GenreDriverImpl{conn: conn}
+}
+
+func (t GenreDriverImpl) GetAll() ([]Genre, error) {
+	genres := []Genre{}
+	if err := t.conn.Order("genre_id").Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+// GetByIds は指定したIDのジャンルを取得する
+func (t GenreDriverImpl) GetByIds(genreIds []uint) ([]Genre, error) {
+	genres := []Genre{}
+	if len(genreIds) == 0 {
+		return genres, nil
+	}
+	if err := t.conn.Where("genre_id IN ?", genreIds).Find(&genres).Error; err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+// CreateGenre はジャンルを作成する
+func (t GenreDriverImpl) CreateGenre(genreName string) (Genre, error) {
+	genre := Genre{GenreName: genreName}
+	if err := t.conn.Create(&genre).Error; err != nil {
+		return Genre{}, err
+	}
+
+	return genre, nil
+}
+
+// UpdateGenre はジャンル名を更新する
+func (t GenreDriverImpl) UpdateGenre(genreId uint, genreName string) (Genre, error) {
+	var genre Genre
+
+	// ジャンルを取得
+	if err := t.conn.First(&genre, genreId).Error; err != nil {
+		return Genre{}, err
+	}
+
+	// ジャンルを更新
+	if err := t.conn.Model(&genre).Updates(Genre{GenreName: genreName}).Error; err != nil {
+		return Genre{}, err
+	}
+
+	return genre, nil
+}
+
+// DeleteGenre はジャンルを削除する
+// cascadeがfalseの場合、メニューから参照されていればErrReferencedを返す
+func (t GenreDriverImpl) DeleteGenre(genreId uint, cascade bool) error {
+	// トランザクション開始
+	tx := t.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// ジャンルの存在確認
+	var genre Genre
+	if err := tx.First(&genre, genreId).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 中間テーブルからの参照を確認
+	var count int64
+	if err := tx.Table("menu_genre_relation").Where("genre_id = ?", genreId).Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count > 0 {
+		if !cascade {
+			tx.Rollback()
+			return ErrReferenced
+		}
+		// 中間テーブルのデータを削除
+		if err := tx.Exec("DELETE FROM menu_genre_relation WHERE genre_id = ?", genreId).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	// ジャンルを削除
+	if err := tx.Delete(&genre).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	return nil
+}
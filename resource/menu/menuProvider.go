@@ -1,16 +1,83 @@
 package menu
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrNotDeleted は復元しようとしたメニューが削除されていないことを表す
+var ErrNotDeleted = errors.New("menu is not deleted")
+
 type MenuDriver interface {
 	GetAll() ([]Menu, error)
-	CreateMenu(menuName string, genreIds []uint, categoryIds []uint) (Menu, error)
-	UpdateMenu(menuId uint, menuName string, genreIds []uint, categoryIds []uint) (Menu, error)
+	EachMenuBatch(batchSize int, fn func(menus []Menu) error) error
+	FindMenus(query MenuQuery) ([]Menu, bool, int64, error)
+	FindCandidates(query CandidateQuery) ([]Menu, error)
+	GetMenu(menuId uint) (Menu, error)
+	GetMenusByIds(menuIds []uint) ([]Menu, error)
+	CreateMenu(menuName string, attributes MenuAttributes, genreIds []uint, categoryIds []uint) (Menu, error)
+	CreateMenus(menus []NewMenu) ([]Menu, error)
+	UpdateMenu(menuId uint, menuName string, attributes MenuAttributes, genreIds []uint, categoryIds []uint) (Menu, error)
 	UpdateGenreRelations(menuId uint, genreIds []uint) (Menu, error)
 	UpdateCategoryRelations(menuId uint, categoryIds []uint) (Menu, error)
+	UpdateAllergens(menuId uint, allergens []string) (Menu, error)
+	UpdateDiets(menuId uint, diets []string) (Menu, error)
+	GetRecipe(menuId uint) (Recipe, error)
+	GetIngredients(menuIds []uint) ([]Recipe, error)
+	UpdateRecipe(menuId uint, ingredients []MenuIngredient, steps []MenuRecipeStep) (Recipe, error)
 	DeleteMenu(menuId uint) error
+	RestoreMenu(menuId uint) (Menu, error)
+	PurgeDeletedMenus(deletedBefore time.Time) (int64, error)
+}
+
+// MenuQuery はメニュー一覧の検索条件
+type MenuQuery struct {
+	Limit int
+	// キーセットページネーションの開始位置（HasAfterがtrueの場合のみ有効）
+	HasAfter  bool
+	AfterId   uint
+	AfterName string
+	// trueの場合はmenu_name、falseの場合はmenu_idで並べる
+	SortByName bool
+	Desc       bool
+	GenreIds   []uint
+	// trueの場合は指定したジャンルをすべて持つメニューに絞り込む
+	GenreAll    bool
+	CategoryIds []uint
+	// trueの場合は指定したカテゴリをすべて持つメニューに絞り込む
+	CategoryAll bool
+	Keyword     string
+	// 指定した値以下のメニューに絞り込む（nilの場合は絞り込まない）
+	MaxPrice    *uint
+	MaxCalories *uint
+	MaxMinutes  *uint
+	// 指定したアレルゲンを含むメニューを除外する
+	ExcludeAllergens []string
+	// 指定した食事制限すべてに対応するメニューに絞り込む
+	RequireDiets []string
+	// trueの場合は論理削除したメニューも含める
+	IncludeDeleted bool
+}
+
+// CandidateQuery はランダムに選ぶ候補メニューの条件
+type CandidateQuery struct {
+	// いずれかのジャンル・カテゴリを持つメニューに絞り込む
+	GenreIds    []uint
+	CategoryIds []uint
+	ExcludeIds  []uint
+	// 指定したアレルゲンを含むメニューを除外する
+	ExcludeAllergens []string
+	// 指定した食事制限すべてに対応するメニューに絞り込む
+	RequireDiets []string
+	// 上限件数に関わらず優先して候補に含めるメニュー
+	PreferIds []uint
+	// 0の場合は件数を制限しない
+	Limit int
+	Seed  int64
 }
 
 type MenuDriverImpl struct {
@@ -25,17 +92,207 @@ func (t MenuDriverImpl) GetAll() ([]Menu, error) {
 	menus := []Menu{}
 	// Preloadで関連データを読み込む
 	// 動作が遅くなる場合はPluckかJoinsを使って最適化する
-	if err := t.conn.Preload("Genres").Preload("Categories").Find(&menus).Error; err != nil {
+	if err := t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets").Find(&menus).Error; err != nil {
 		return nil, err
 	}
 
 	return menus, nil
 }
 
+// EachMenuBatch はすべてのメニューをmenu_id順にbatchSize件ずつ取得し、fnに渡す
+// GetAllと異なり、全件を一度にメモリに読み込まない
+// fnがエラーを返した場合はそこで取得を中断する
+func (t MenuDriverImpl) EachMenuBatch(batchSize int, fn func(menus []Menu) error) error {
+	var menus []Menu
+	// FindInBatchesは主キー（menu_id）順にキーセットで取得する
+	return t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets").
+		FindInBatches(&menus, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn(menus)
+		}).Error
+}
+
+// FindMenus は条件に一致するメニューを1ページ分取得する
+// 戻り値: (メニュー, 次のページがあるか, 条件に一致する件数, エラー)
+func (t MenuDriverImpl) FindMenus(query MenuQuery) ([]Menu, bool, int64, error) {
+	conn := t.conn
+	if query.IncludeDeleted {
+		conn = conn.Unscoped()
+	}
+
+	// 件数を取得（ページ位置は含めない）
+	var total int64
+	if err := t.filterMenus(conn.Model(&Menu{}), query).Count(&total).Error; err != nil {
+		return nil, false, 0, err
+	}
+
+	db := t.filterMenus(conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets"), query)
+
+	// 並び順とキーセットの条件
+	op, order := ">", "ASC"
+	if query.Desc {
+		op, order = "<", "DESC"
+	}
+	if query.SortByName {
+		if query.HasAfter {
+			db = db.Where("(menu_name "+op+" ?) OR (menu_name = ? AND menu_id "+op+" ?)", query.AfterName, query.AfterName, query.AfterId)
+		}
+		db = db.Order("menu_name " + order).Order("menu_id " + order)
+	} else {
+		if query.HasAfter {
+			db = db.Where("menu_id "+op+" ?", query.AfterId)
+		}
+		db = db.Order("menu_id " + order)
+	}
+
+	// 次のページの有無を判定するため1件多く取得する
+	menus := []Menu{}
+	if err := db.Limit(query.Limit + 1).Find(&menus).Error; err != nil {
+		return nil, false, 0, err
+	}
+
+	hasNext := len(menus) > query.Limit
+	if hasNext {
+		menus = menus[:query.Limit]
+	}
+
+	return menus, hasNext, total, nil
+}
+
+// FindCandidates はランダムに選ぶ候補のメニューを取得する（論理削除したメニューは含めない）
+// 上限件数を超える場合は、PreferIdsのメニューを優先し、残りはシードで決まる順に絞り込む
+func (t MenuDriverImpl) FindCandidates(query CandidateQuery) ([]Menu, error) {
+	db := t.filterMenus(t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets"), MenuQuery{
+		GenreIds:         query.GenreIds,
+		CategoryIds:      query.CategoryIds,
+		ExcludeAllergens: query.ExcludeAllergens,
+		RequireDiets:     query.RequireDiets,
+	})
+	if len(query.ExcludeIds) > 0 {
+		db = db.Where("menu_id NOT IN ?", query.ExcludeIds)
+	}
+
+	if query.Limit > 0 {
+		// PreferIdsを優先し、残りは取得順に依存せず同じシードであれば同じメニューに絞り込む
+		order := clause.Expr{SQL: "CRC32(CONCAT(?, ':', menu_id)), menu_id", Vars: []interface{}{query.Seed}, WithoutParentheses: true}
+		if len(query.PreferIds) > 0 {
+			order = clause.Expr{SQL: "menu_id IN (?) DESC, " + order.SQL, Vars: []interface{}{query.PreferIds, query.Seed}, WithoutParentheses: true}
+		}
+		db = db.Order(clause.OrderBy{Expression: order}).Limit(query.Limit)
+	}
+
+	menus := []Menu{}
+	if err := db.Find(&menus).Error; err != nil {
+		return nil, err
+	}
+
+	return menus, nil
+}
+
+// GetMenu はメニューを1件取得する
+func (t MenuDriverImpl) GetMenu(menuId uint) (Menu, error) {
+	var menu Menu
+	if err := t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets").First(&menu, menuId).Error; err != nil {
+		return Menu{}, err
+	}
+
+	return menu, nil
+}
+
+// GetMenusByIds は指定したIDのメニューを取得する（存在しない・論理削除したメニューは含めない）
+func (t MenuDriverImpl) GetMenusByIds(menuIds []uint) ([]Menu, error) {
+	menus := []Menu{}
+	if len(menuIds) == 0 {
+		return menus, nil
+	}
+	if err := t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets").Where("menu_id IN ?", menuIds).Find(&menus).Error; err != nil {
+		return nil, err
+	}
+
+	return menus, nil
+}
+
+// filterMenus は検索条件の絞り込みをクエリに追加する
+func (t MenuDriverImpl) filterMenus(db *gorm.DB, query MenuQuery) *gorm.DB {
+	if query.Keyword != "" {
+		db = db.Where("menu_name LIKE ?", "%"+escapeLike(query.Keyword)+"%")
+	}
+	if query.MaxPrice != nil {
+		db = db.Where("price <= ?", *query.MaxPrice)
+	}
+	if query.MaxCalories != nil {
+		db = db.Where("calories <= ?", *query.MaxCalories)
+	}
+	if query.MaxMinutes != nil {
+		db = db.Where("cooking_minutes <= ?", *query.MaxMinutes)
+	}
+	if len(query.ExcludeAllergens) > 0 {
+		db = db.Where("menu_id NOT IN (?)", t.conn.Model(&MenuAllergen{}).Select("menu_id").Where("allergen IN ?", query.ExcludeAllergens))
+	}
+	if len(query.RequireDiets) > 0 {
+		db = db.Where("menu_id IN (?)", relationSubQuery(t.conn, "menu_diet_relation", "diet", query.RequireDiets, true))
+	}
+	if len(query.GenreIds) > 0 {
+		db = db.Where("menu_id IN (?)", relationSubQuery(t.conn, "menu_genre_relation", "genre_id", query.GenreIds, query.GenreAll))
+	}
+	if len(query.CategoryIds) > 0 {
+		db = db.Where("menu_id IN (?)", relationSubQuery(t.conn, "menu_category_relation", "category_id", query.CategoryIds, query.CategoryAll))
+	}
+
+	return db
+}
+
+// relationSubQuery は中間テーブルから条件に一致するmenu_idを取得するサブクエリを作成する
+func relationSubQuery[T comparable](conn *gorm.DB, table string, column string, ids []T, matchAll bool) *gorm.DB {
+	sub := conn.Table(table).Select("menu_id").Where(column+" IN ?", ids)
+	if matchAll {
+		sub = sub.Group("menu_id").Having("COUNT(DISTINCT "+column+") = ?", countDistinct(ids))
+	}
+
+	return sub
+}
+
+// escapeLike はLIKE句のワイルドカードをエスケープする
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// countDistinct は重複を除いた値の件数を返す
+func countDistinct[T comparable](ids []T) int {
+	seen := map[T]bool{}
+	for _, id := range ids {
+		seen[id] = true
+	}
+
+	return len(seen)
+}
+
 // CreateMenu はメニューを作成する
-func (t MenuDriverImpl) CreateMenu(menuName string, genreIds []uint, categoryIds []uint) (Menu, error) {
-	menu := Menu{MenuName: menuName}
+func (t MenuDriverImpl) CreateMenu(menuName string, attributes MenuAttributes, genreIds []uint, categoryIds []uint) (Menu, error) {
+	// トランザクション開始
+	tx := t.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	menu, err := createMenu(tx, NewMenu{MenuName: menuName, MenuAttributes: attributes, GenreIds: genreIds, CategoryIds: categoryIds})
+	if err != nil {
+		tx.Rollback()
+		return Menu{}, err
+	}
 
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return Menu{}, err
+	}
+
+	return menu, nil
+}
+
+// CreateMenus は複数のメニューを1つのトランザクションで作成する
+// いずれかの作成に失敗した場合は何も作成しない
+func (t MenuDriverImpl) CreateMenus(newMenus []NewMenu) ([]Menu, error) {
 	// トランザクション開始
 	tx := t.conn.Begin()
 	defer func() {
@@ -44,38 +301,58 @@ func (t MenuDriverImpl) CreateMenu(menuName string, genreIds []uint, categoryIds
 		}
 	}()
 
+	menus := []Menu{}
+	for _, newMenu := range newMenus {
+		menu, err := createMenu(tx, newMenu)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		menus = append(menus, menu)
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return menus, nil
+}
+
+// NewMenu は作成するメニューの内容
+type NewMenu struct {
+	MenuName string
+	MenuAttributes
+	GenreIds    []uint
+	CategoryIds []uint
+}
+
+// createMenu はトランザクション内でメニューとジャンル・カテゴリの関連を作成する
+func createMenu(tx *gorm.DB, newMenu NewMenu) (Menu, error) {
+	menu := Menu{MenuName: newMenu.MenuName, MenuAttributes: newMenu.MenuAttributes}
+
 	// メニューを作成
 	if err := tx.Create(&menu).Error; err != nil {
-		tx.Rollback()
 		return Menu{}, err
 	}
 
 	// ジャンルを取得
 	var genres []Genre
-	if err := tx.Where("genre_id IN ?", genreIds).Find(&genres).Error; err != nil {
-		tx.Rollback()
+	if err := tx.Where("genre_id IN ?", newMenu.GenreIds).Find(&genres).Error; err != nil {
 		return Menu{}, err
 	}
 	// 中間テーブルにデータを追加
 	if err := tx.Model(&menu).Association("Genres").Append(genres); err != nil {
-		tx.Rollback()
 		return Menu{}, err
 	}
 
 	// カテゴリを取得
 	var categories []Category
-	if err := tx.Where("category_id IN ?", categoryIds).Find(&categories).Error; err != nil {
-		tx.Rollback()
+	if err := tx.Where("category_id IN ?", newMenu.CategoryIds).Find(&categories).Error; err != nil {
 		return Menu{}, err
 	}
 	// 中間テーブルにデータを追加
 	if err := tx.Model(&menu).Association("Categories").Append(categories); err != nil {
-		tx.Rollback()
-		return Menu{}, err
-	}
-
-	// コミット
-	if err := tx.Commit().Error; err != nil {
 		return Menu{}, err
 	}
 
@@ -83,11 +360,11 @@ func (t MenuDriverImpl) CreateMenu(menuName string, genreIds []uint, categoryIds
 }
 
 // UpdateMenu はメニューを更新する
-func (t MenuDriverImpl) UpdateMenu(menuId uint, menuName string, genreIds []uint, categoryIds []uint) (Menu, error) {
+func (t MenuDriverImpl) UpdateMenu(menuId uint, menuName string, attributes MenuAttributes, genreIds []uint, categoryIds []uint) (Menu, error) {
 	var menu Menu
 
 	// メニューを取得
-	if err := t.conn.Preload("Genres").Preload("Categories").First(&menu, menuId).Error; err != nil {
+	if err := t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets").First(&menu, menuId).Error; err != nil {
 		return Menu{}, err
 	}
 
@@ -99,8 +376,8 @@ func (t MenuDriverImpl) UpdateMenu(menuId uint, menuName string, genreIds []uint
 		}
 	}()
 
-	// メニューを更新
-	if err := tx.Model(&menu).Updates(Menu{MenuName: menuName}).Error; err != nil {
+	// メニューを更新（任意項目は空の値でも上書きする）
+	if err := tx.Model(&menu).Select(append([]string{"MenuName"}, MenuAttributeColumns...)).Updates(Menu{MenuName: menuName, MenuAttributes: attributes}).Error; err != nil {
 		tx.Rollback()
 		return Menu{}, err
 	}
@@ -142,7 +419,7 @@ func (t MenuDriverImpl) UpdateGenreRelations(menuId uint, genreIds []uint) (Menu
 	var menu Menu
 
 	// メニューを取得
-	if err := t.conn.Preload("Genres").Preload("Categories").First(&menu, menuId).Error; err != nil {
+	if err := t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets").First(&menu, menuId).Error; err != nil {
 		return Menu{}, err
 	}
 
@@ -178,7 +455,7 @@ func (t MenuDriverImpl) UpdateCategoryRelations(menuId uint, categoryIds []uint)
 	var menu Menu
 
 	// メニューを取得
-	if err := t.conn.Preload("Genres").Preload("Categories").First(&menu, menuId).Error; err != nil {
+	if err := t.conn.Preload("Genres").Preload("Categories").Preload("Allergens").Preload("Diets").First(&menu, menuId).Error; err != nil {
 		return Menu{}, err
 	}
 
@@ -209,8 +486,43 @@ func (t MenuDriverImpl) UpdateCategoryRelations(menuId uint, categoryIds []uint)
 	return menu, nil
 }
 
-// DeleteMenu はメニューを削除する
+// DeleteMenu はメニューを論理削除する
+// 関連データは復元できるよう残し、保持期間の経過後にPurgeDeletedMenusで削除する
 func (t MenuDriverImpl) DeleteMenu(menuId uint) error {
+	result := t.conn.Delete(&Menu{}, menuId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// RestoreMenu は論理削除したメニューを復元する
+// 削除されていないメニューの場合はErrNotDeletedを返す
+func (t MenuDriverImpl) RestoreMenu(menuId uint) (Menu, error) {
+	var menu Menu
+	if err := t.conn.Unscoped().First(&menu, menuId).Error; err != nil {
+		return Menu{}, err
+	}
+	if !menu.DeletedAt.Valid {
+		return Menu{}, ErrNotDeleted
+	}
+
+	if err := t.conn.Unscoped().Model(&menu).Update("deleted_at", nil).Error; err != nil {
+		return Menu{}, err
+	}
+
+	return t.GetMenu(menuId)
+}
+
+// PurgeDeletedMenus は指定日時より前に論理削除したメニューを物理削除する
+// 中間テーブル・アレルゲン・食事制限・レシピ・お気に入りに加えて、
+// メニューを参照する食事履歴・献立・提案も同じトランザクションで削除する
+// 戻り値: 削除したメニューの件数
+func (t MenuDriverImpl) PurgeDeletedMenus(deletedBefore time.Time) (int64, error) {
 	// トランザクション開始
 	tx := t.conn.Begin()
 	defer func() {
@@ -219,26 +531,75 @@ func (t MenuDriverImpl) DeleteMenu(menuId uint) error {
 		}
 	}()
 
-	// メニューを削除
-	if err := tx.Delete(&Menu{}, menuId).Error; err != nil {
+	// 対象のメニューを取得
+	var menuIds []uint
+	if err := tx.Unscoped().Model(&Menu{}).Where("deleted_at < ?", deletedBefore).Pluck("menu_id", &menuIds).Error; err != nil {
 		tx.Rollback()
-		return err
+		return 0, err
+	}
+	if len(menuIds) == 0 {
+		tx.Rollback()
+		return 0, nil
+	}
+
+	// 関連データを削除（お気に入りは外部キー制約により削除される）
+	// 食事履歴・献立・提案はmenu_idがNOT NULLで外部キー制約もないため、残さないよう明示的に削除する
+	for _, table := range []string{"menu_genre_relation", "menu_category_relation", "meal_history", "meal_plans", "suggestions"} {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE menu_id IN ?", menuIds).Error; err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+	for _, model := range []interface{}{&MenuAllergen{}, &MenuDiet{}, &MenuIngredient{}, &MenuRecipeStep{}} {
+		if err := tx.Where("menu_id IN ?", menuIds).Delete(model).Error; err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+	}
+
+	// メニューを物理削除
+	result := tx.Unscoped().Delete(&Menu{}, menuIds)
+	if result.Error != nil {
+		tx.Rollback()
+		return 0, result.Error
 	}
 
 	// コミット
 	if err := tx.Commit().Error; err != nil {
-		return err
+		return 0, err
 	}
 
-	return nil
+	return result.RowsAffected, nil
 }
 
+// MenuAttributes はメニューの任意項目
+type MenuAttributes struct {
+	Description string `gorm:"type:text;column:description" json:"description"`
+	// 価格（円）
+	Price    *uint `gorm:"column:price" json:"price"`
+	Calories *uint `gorm:"column:calories" json:"calories"`
+	// 調理時間（分）
+	CookingMinutes *uint  `gorm:"column:cooking_minutes" json:"cooking_minutes"`
+	ImageUrl       string `gorm:"size:2048;column:image_url" json:"image_url"`
+}
+
+// MenuAttributeColumns はMenuAttributesで追加した列のフィールド名
+var MenuAttributeColumns = []string{"Description", "Price", "Calories", "CookingMinutes", "ImageUrl"}
+
+// MenuSoftDeleteColumns は論理削除のために追加した列のフィールド名
+var MenuSoftDeleteColumns = []string{"DeletedAt"}
+
 type Menu struct {
 	MenuId   uint   `gorm:"primaryKey" json:"id"`
 	MenuName string `gorm:"size:50;column:menu_name" json:"menu_name"`
+	MenuAttributes
 	// many2many タグで中間テーブルを指定
-	Genres     []Genre    `gorm:"many2many:menu_genre_relation;joinForeignKey:menu_id;JoinReferences:genre_id"`
-	Categories []Category `gorm:"many2many:menu_category_relation;joinForeignKey:menu_id;JoinReferences:category_id"`
+	Genres     []Genre        `gorm:"many2many:menu_genre_relation;joinForeignKey:menu_id;JoinReferences:genre_id"`
+	Categories []Category     `gorm:"many2many:menu_category_relation;joinForeignKey:menu_id;JoinReferences:category_id"`
+	Allergens  []MenuAllergen `gorm:"foreignKey:MenuId"`
+	Diets      []MenuDiet     `gorm:"foreignKey:MenuId"`
+	// 論理削除した日時
+	DeletedAt gorm.DeletedAt `gorm:"index;column:deleted_at" json:"deleted_at"`
 }
 
 func (Menu) TableName() string {
@@ -0,0 +1,162 @@
+package menu
+
+// MenuIngredient はメニューの材料
+type MenuIngredient struct {
+	IngredientId uint `gorm:"primaryKey;column:ingredient_id" json:"ingredient_id"`
+	MenuId       uint `gorm:"index;column:menu_id;not null" json:"menu_id"`
+	// 材料の表示順（0始まり）
+	Position int    `gorm:"column:position;not null" json:"position"`
+	Name     string `gorm:"size:100;column:name;not null" json:"name"`
+	// 1人前の分量
+	Quantity float64 `gorm:"column:quantity" json:"quantity"`
+	Unit     string  `gorm:"size:32;column:unit" json:"unit"`
+	// 売り場（買い物リストのグループ分けに使う）
+	Section string `gorm:"size:32;column:section;not null;default:other" json:"section"`
+}
+
+func (MenuIngredient) TableName() string {
+	return "menu_ingredients"
+}
+
+// MenuRecipeStep はメニューの調理手順
+type MenuRecipeStep struct {
+	MenuId uint `gorm:"primaryKey;column:menu_id;autoIncrement:false" json:"menu_id"`
+	// 手順の番号（1始まり）
+	StepNumber  int    `gorm:"primaryKey;column:step_number;autoIncrement:false" json:"step_number"`
+	Instruction string `gorm:"type:text;column:instruction;not null" json:"instruction"`
+}
+
+func (MenuRecipeStep) TableName() string {
+	return "menu_recipe_steps"
+}
+
+// Recipe はメニューの材料と調理手順
+type Recipe struct {
+	MenuId      uint
+	Ingredients []MenuIngredient
+	Steps       []MenuRecipeStep
+}
+
+// GetRecipe はメニューの材料と調理手順を取得する
+func (t MenuDriverImpl) GetRecipe(menuId uint) (Recipe, error) {
+	// メニューの存在確認
+	if _, err := t.GetMenu(menuId); err != nil {
+		return Recipe{}, err
+	}
+
+	recipe := Recipe{MenuId: menuId}
+
+	if err := t.conn.Where("menu_id = ?", menuId).Order("position").Find(&recipe.Ingredients).Error; err != nil {
+		return Recipe{}, err
+	}
+	if err := t.conn.Where("menu_id = ?", menuId).Order("step_number").Find(&recipe.Steps).Error; err != nil {
+		return Recipe{}, err
+	}
+
+	return recipe, nil
+}
+
+// GetIngredients は複数メニューの材料をまとめて取得する
+// 存在しない・削除済みのメニューは結果に含めない（調理手順は取得しない）
+func (t MenuDriverImpl) GetIngredients(menuIds []uint) ([]Recipe, error) {
+	recipes := []Recipe{}
+	if len(menuIds) == 0 {
+		return recipes, nil
+	}
+
+	// 削除されていないメニューだけを対象にする
+	var existingIds []uint
+	if err := t.conn.Model(&Menu{}).Where("menu_id IN ?", menuIds).Pluck("menu_id", &existingIds).Error; err != nil {
+		return nil, err
+	}
+	if len(existingIds) == 0 {
+		return recipes, nil
+	}
+
+	ingredients := []MenuIngredient{}
+	if err := t.conn.Where("menu_id IN ?", existingIds).Order("menu_id").Order("position").Find(&ingredients).Error; err != nil {
+		return nil, err
+	}
+
+	ingredientsOf := map[uint][]MenuIngredient{}
+	for _, ingredient := range ingredients {
+		ingredientsOf[ingredient.MenuId] = append(ingredientsOf[ingredient.MenuId], ingredient)
+	}
+
+	// 引数の並び順で返す
+	existing := map[uint]bool{}
+	for _, menuId := range existingIds {
+		existing[menuId] = true
+	}
+	for _, menuId := range menuIds {
+		if !existing[menuId] {
+			continue
+		}
+		recipes = append(recipes, Recipe{MenuId: menuId, Ingredients: ingredientsOf[menuId]})
+	}
+
+	return recipes, nil
+}
+
+// UpdateRecipe はメニューの材料と調理手順を置き換える
+// 表示順と手順の番号は引数の並び順から採番する
+func (t MenuDriverImpl) UpdateRecipe(menuId uint, ingredients []MenuIngredient, steps []MenuRecipeStep) (Recipe, error) {
+	// メニューの存在確認
+	if _, err := t.GetMenu(menuId); err != nil {
+		return Recipe{}, err
+	}
+
+	// トランザクション開始
+	tx := t.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 既存の材料と調理手順を削除
+	if err := tx.Where("menu_id = ?", menuId).Delete(&MenuIngredient{}).Error; err != nil {
+		tx.Rollback()
+		return Recipe{}, err
+	}
+	if err := tx.Where("menu_id = ?", menuId).Delete(&MenuRecipeStep{}).Error; err != nil {
+		tx.Rollback()
+		return Recipe{}, err
+	}
+
+	// 材料を追加
+	if len(ingredients) > 0 {
+		rows := []MenuIngredient{}
+		for i, ingredient := range ingredients {
+			ingredient.IngredientId = 0
+			ingredient.MenuId = menuId
+			ingredient.Position = i
+			rows = append(rows, ingredient)
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return Recipe{}, err
+		}
+	}
+
+	// 調理手順を追加
+	if len(steps) > 0 {
+		rows := []MenuRecipeStep{}
+		for i, step := range steps {
+			step.MenuId = menuId
+			step.StepNumber = i + 1
+			rows = append(rows, step)
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return Recipe{}, err
+		}
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return Recipe{}, err
+	}
+
+	return t.GetRecipe(menuId)
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	"errors"
+	"go-menu/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarToken はカレンダー購読URLの秘密トークンのためのcalendar_tokensテーブルを表します
+// トークンそのものは保存せず、SHA-256ハッシュのみを保存します
+type CalendarToken struct {
+	UserID    uint      `gorm:"primaryKey;autoIncrement:false;column:user_id" json:"user_id"`
+	TokenHash string    `gorm:"type:char(64);uniqueIndex;not null;column:token_hash" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (CalendarToken) TableName() string {
+	return "calendar_tokens"
+}
+
+// SaveCalendarToken はユーザーのカレンダートークンを保存します（既存のトークンは無効になります）
+func (u UserDriverImpl) SaveCalendarToken(userID uint, tokenHash string) (CalendarToken, error) {
+	token := CalendarToken{
+		UserID:    userID,
+		TokenHash: tokenHash,
+	}
+
+	// トランザクション開始
+	tx := u.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 既存のトークンを削除
+	if err := tx.Where("user_id = ?", userID).Delete(&CalendarToken{}).Error; err != nil {
+		tx.Rollback()
+		return CalendarToken{}, err
+	}
+
+	if err := tx.Create(&token).Error; err != nil {
+		tx.Rollback()
+		return CalendarToken{}, err
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return CalendarToken{}, err
+	}
+
+	return token, nil
+}
+
+// DeleteCalendarToken はユーザーのカレンダートークンを削除します
+func (u UserDriverImpl) DeleteCalendarToken(userID uint) error {
+	result := u.conn.Where("user_id = ?", userID).Delete(&CalendarToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.NewNotFound("calendar_token_not_found", "calendar token not found")
+	}
+	return nil
+}
+
+// GetCalendarTokenByHash はトークンのハッシュからカレンダートークンを取得します
+func (u UserDriverImpl) GetCalendarTokenByHash(tokenHash string) (CalendarToken, error) {
+	var token CalendarToken
+	err := u.conn.Where("token_hash = ?", tokenHash).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return CalendarToken{}, domain.NewNotFound("calendar_token_not_found", "calendar token not found").Wrap(err)
+	}
+	return token, err
+}
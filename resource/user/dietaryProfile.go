@@ -0,0 +1,113 @@
+package user
+
+import "gorm.io/gorm"
+
+// UserAllergen はユーザーが避けるアレルゲンのためのuser_allergensテーブルを表します
+type UserAllergen struct {
+	UserID   uint   `gorm:"primaryKey;column:user_id;autoIncrement:false" json:"user_id"`
+	Allergen string `gorm:"primaryKey;size:32;column:allergen" json:"allergen"`
+}
+
+func (UserAllergen) TableName() string {
+	return "user_allergens"
+}
+
+// UserDiet はユーザーが必要とする食事制限のためのuser_dietsテーブルを表します
+type UserDiet struct {
+	UserID uint   `gorm:"primaryKey;column:user_id;autoIncrement:false" json:"user_id"`
+	Diet   string `gorm:"primaryKey;size:32;column:diet" json:"diet"`
+}
+
+func (UserDiet) TableName() string {
+	return "user_diets"
+}
+
+// DietaryProfile はユーザーの食事制限プロファイルを表します
+type DietaryProfile struct {
+	// 含まれるメニューを除外するアレルゲン
+	Allergens []string
+	// 対応しているメニューのみを表示する食事制限
+	Diets []string
+}
+
+// GetDietaryProfile はユーザーの食事制限プロファイルを取得します
+func (u UserDriverImpl) GetDietaryProfile(userID uint) (DietaryProfile, error) {
+	profile := DietaryProfile{Allergens: []string{}, Diets: []string{}}
+
+	if err := u.conn.Model(&UserAllergen{}).Where("user_id = ?", userID).Order("allergen").Pluck("allergen", &profile.Allergens).Error; err != nil {
+		return DietaryProfile{}, err
+	}
+	if err := u.conn.Model(&UserDiet{}).Where("user_id = ?", userID).Order("diet").Pluck("diet", &profile.Diets).Error; err != nil {
+		return DietaryProfile{}, err
+	}
+
+	return profile, nil
+}
+
+// UpdateDietaryProfile はユーザーの食事制限プロファイルを置き換えます
+func (u UserDriverImpl) UpdateDietaryProfile(userID uint, allergens []string, diets []string) (DietaryProfile, error) {
+	// トランザクション開始
+	tx := u.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 既存のプロファイルを削除
+	if err := tx.Where("user_id = ?", userID).Delete(&UserAllergen{}).Error; err != nil {
+		tx.Rollback()
+		return DietaryProfile{}, err
+	}
+	if err := tx.Where("user_id = ?", userID).Delete(&UserDiet{}).Error; err != nil {
+		tx.Rollback()
+		return DietaryProfile{}, err
+	}
+
+	// プロファイルを追加
+	if len(allergens) > 0 {
+		rows := []UserAllergen{}
+		for _, allergen := range allergens {
+			rows = append(rows, UserAllergen{UserID: userID, Allergen: allergen})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return DietaryProfile{}, err
+		}
+	}
+	if len(diets) > 0 {
+		rows := []UserDiet{}
+		for _, diet := range diets {
+			rows = append(rows, UserDiet{UserID: userID, Diet: diet})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			tx.Rollback()
+			return DietaryProfile{}, err
+		}
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return DietaryProfile{}, err
+	}
+
+	return u.GetDietaryProfile(userID)
+}
+
+// visibleFavorites はユーザーの食事制限プロファイルに反しないお気に入りに絞り込む
+func (u UserDriverImpl) visibleFavorites(userID uint) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("favorites.user_id = ?", userID).
+			// ユーザーが避けるアレルゲンを含むメニューを除外
+			Where("favorites.menu_id NOT IN (?)", u.conn.Table("menu_allergen_relation AS mar").
+				Select("mar.menu_id").
+				Joins("JOIN user_allergens AS ua ON ua.allergen = mar.allergen").
+				Where("ua.user_id = ?", userID)).
+			// ユーザーが必要とする食事制限のいずれかに対応していないメニューを除外
+			Where("NOT EXISTS (?)", u.conn.Table("user_diets AS ud").
+				Select("1").
+				Where("ud.user_id = ?", userID).
+				Where("NOT EXISTS (SELECT 1 FROM menu_diet_relation AS mdr WHERE mdr.menu_id = favorites.menu_id AND mdr.diet = ud.diet)"))
+	}
+}
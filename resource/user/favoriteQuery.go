@@ -0,0 +1,56 @@
+package user
+
+import "time"
+
+// FavoriteQuery はお気に入り一覧の検索条件
+type FavoriteQuery struct {
+	UserID uint
+	Limit  int
+	// キーセットページネーションの開始位置（HasAfterがtrueの場合のみ有効）
+	HasAfter       bool
+	AfterCreatedAt time.Time
+	AfterID        uint
+	// trueの場合は新しい順に並べる
+	Desc bool
+}
+
+// FindFavorites はユーザーの食事制限プロファイルに反しないお気に入りをメニューとともに取得します
+// 論理削除したメニューのお気に入りも含みます
+// 戻り値: (お気に入り, 次のページがあるか, 全件数, error)
+func (u UserDriverImpl) FindFavorites(query FavoriteQuery) ([]Favorite, bool, int64, error) {
+	// 件数を取得（ページ位置は含めない）
+	var total int64
+	if err := u.conn.Model(&Favorite{}).Scopes(u.visibleFavorites(query.UserID)).Count(&total).Error; err != nil {
+		return nil, false, 0, err
+	}
+
+	// メニューは結合して1回のクエリで取得し、ジャンル・カテゴリなどはまとめて読み込む
+	db := u.conn.Unscoped().
+		Joins("Menu").
+		Preload("Menu.Genres").Preload("Menu.Categories").Preload("Menu.Allergens").Preload("Menu.Diets").
+		Scopes(u.visibleFavorites(query.UserID))
+
+	// 並び順とキーセットの条件
+	op, order := ">", "ASC"
+	if query.Desc {
+		op, order = "<", "DESC"
+	}
+	if query.HasAfter {
+		db = db.Where("(favorites.created_at "+op+" ?) OR (favorites.created_at = ? AND favorites.favorite_id "+op+" ?)",
+			query.AfterCreatedAt, query.AfterCreatedAt, query.AfterID)
+	}
+	db = db.Order("favorites.created_at " + order).Order("favorites.favorite_id " + order)
+
+	// 次のページの有無を判定するため1件多く取得する
+	favorites := []Favorite{}
+	if err := db.Limit(query.Limit + 1).Find(&favorites).Error; err != nil {
+		return nil, false, 0, err
+	}
+
+	hasNext := len(favorites) > query.Limit
+	if hasNext {
+		favorites = favorites[:query.Limit]
+	}
+
+	return favorites, hasNext, total, nil
+}
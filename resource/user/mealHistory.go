@@ -0,0 +1,81 @@
+package user
+
+import (
+	"errors"
+	"go-menu/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MealHistory はユーザーが実際に食べたメニューのためのmeal_historyテーブルを表します
+type MealHistory struct {
+	HistoryID uint      `gorm:"primaryKey;column:history_id" json:"history_id"`
+	UserID    uint      `gorm:"not null;column:user_id;index:idx_meal_history_user_date,priority:1" json:"user_id"`
+	MenuID    uint      `gorm:"not null;column:menu_id;index" json:"menu_id"`
+	EatenOn   time.Time `gorm:"type:date;not null;column:eaten_on;index:idx_meal_history_user_date,priority:2" json:"eaten_on"`
+	MealSlot  string    `gorm:"type:varchar(16);not null;column:meal_slot" json:"meal_slot"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (MealHistory) TableName() string {
+	return "meal_history"
+}
+
+// AddMealHistory は食事履歴を追加します
+func (u UserDriverImpl) AddMealHistory(userID, menuID uint, eatenOn time.Time, mealSlot string) (MealHistory, error) {
+	// メニュー存在チェック：メニューテーブルにmenu_idが存在するかを確認
+	var menuCount int64
+	err := u.conn.Table("menu_list").Where("menu_id = ? AND deleted_at IS NULL", menuID).Count(&menuCount).Error
+	if err != nil {
+		return MealHistory{}, err
+	}
+	if menuCount == 0 {
+		return MealHistory{}, domain.NewNotFound("menu_not_found", "menu not found")
+	}
+
+	history := MealHistory{
+		UserID:   userID,
+		MenuID:   menuID,
+		EatenOn:  eatenOn,
+		MealSlot: mealSlot,
+	}
+
+	err = u.conn.Create(&history).Error
+	return history, err
+}
+
+// GetMealHistories はユーザーの指定期間（両端を含む）の食事履歴を新しい順に取得します
+// from, toがゼロ値の場合はその方向の期間を制限しません
+func (u UserDriverImpl) GetMealHistories(userID uint, from, to time.Time) ([]MealHistory, error) {
+	var histories []MealHistory
+
+	query := u.conn.Where("user_id = ?", userID)
+	if !from.IsZero() {
+		query = query.Where("eaten_on >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("eaten_on <= ?", to)
+	}
+
+	err := query.
+		Order("eaten_on DESC").
+		Order("FIELD(meal_slot, 'dinner', 'lunch', 'breakfast')").
+		Find(&histories).Error
+	return histories, err
+}
+
+// GetMealHistoryByID は履歴IDで食事履歴を取得します
+func (u UserDriverImpl) GetMealHistoryByID(historyID uint) (MealHistory, error) {
+	var history MealHistory
+	err := u.conn.First(&history, historyID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return MealHistory{}, domain.NewNotFound("history_not_found", "meal history not found").Wrap(err)
+	}
+	return history, err
+}
+
+// RemoveMealHistoryByID は履歴IDで食事履歴を削除します
+func (u UserDriverImpl) RemoveMealHistoryByID(historyID uint) error {
+	return u.conn.Delete(&MealHistory{}, historyID).Error
+}
@@ -0,0 +1,198 @@
+package user
+
+import (
+	"errors"
+	"go-menu/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MealPlan はユーザーの献立（日付×時間帯→メニュー）のためのmeal_plansテーブルを表します
+type MealPlan struct {
+	PlanID    uint      `gorm:"primaryKey;column:plan_id" json:"plan_id"`
+	UserID    uint      `gorm:"not null;column:user_id;uniqueIndex:idx_meal_plans_user_slot,priority:1" json:"user_id"`
+	MenuID    uint      `gorm:"not null;column:menu_id;index" json:"menu_id"`
+	PlannedOn time.Time `gorm:"type:date;not null;column:planned_on;uniqueIndex:idx_meal_plans_user_slot,priority:2" json:"planned_on"`
+	MealSlot  string    `gorm:"type:varchar(16);not null;column:meal_slot;uniqueIndex:idx_meal_plans_user_slot,priority:3" json:"meal_slot"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 献立のメニューがmenu_listに存在し、削除されていないか（取得時のみ設定）
+	MenuExists bool `gorm:"->;-:migration;column:menu_exists" json:"menu_exists"`
+	// 献立のメニュー名（取得時のみ設定）
+	MenuName string `gorm:"->;-:migration;column:menu_name" json:"menu_name"`
+}
+
+func (MealPlan) TableName() string {
+	return "meal_plans"
+}
+
+// MealPlanEntry は献立に追加する内容を表します
+type MealPlanEntry struct {
+	PlannedOn time.Time
+	MealSlot  string
+	MenuID    uint
+}
+
+// mealPlanColumns は献立の取得時に選択する列（メニューの存在有無と名前を含む）
+const mealPlanColumns = "meal_plans.*, " +
+	"EXISTS (SELECT 1 FROM menu_list WHERE menu_list.menu_id = meal_plans.menu_id AND menu_list.deleted_at IS NULL) AS menu_exists, " +
+	"COALESCE((SELECT menu_name FROM menu_list WHERE menu_list.menu_id = meal_plans.menu_id), '') AS menu_name"
+
+// AddMealPlans は献立をまとめて追加します
+// いずれかのメニューが存在しない場合や、時間帯に既に献立がある場合は何も追加しません
+func (u UserDriverImpl) AddMealPlans(userID uint, entries []MealPlanEntry) ([]MealPlan, error) {
+	if len(entries) == 0 {
+		return []MealPlan{}, nil
+	}
+
+	// メニュー存在チェック：メニューテーブルにmenu_idがすべて存在するかを確認
+	menuIDs := map[uint]bool{}
+	for _, entry := range entries {
+		menuIDs[entry.MenuID] = true
+	}
+	ids := make([]uint, 0, len(menuIDs))
+	for id := range menuIDs {
+		ids = append(ids, id)
+	}
+	var menuCount int64
+	if err := u.conn.Table("menu_list").Where("menu_id IN ? AND deleted_at IS NULL", ids).Count(&menuCount).Error; err != nil {
+		return nil, err
+	}
+	if menuCount != int64(len(ids)) {
+		return nil, domain.NewNotFound("menu_not_found", "menu not found")
+	}
+
+	// トランザクション開始
+	tx := u.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	planIDs := []uint{}
+	for _, entry := range entries {
+		// 時間帯の空きチェック
+		if err := ensureMealSlotAvailable(tx, userID, entry.PlannedOn, entry.MealSlot, 0); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		plan := MealPlan{
+			UserID:    userID,
+			MenuID:    entry.MenuID,
+			PlannedOn: entry.PlannedOn,
+			MealSlot:  entry.MealSlot,
+		}
+		if err := tx.Create(&plan).Error; err != nil {
+			tx.Rollback()
+			// 空きチェック後に同じ時間帯へ同時に追加された場合
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return nil, mealSlotTaken(entry.PlannedOn, entry.MealSlot).Wrap(err)
+			}
+			return nil, err
+		}
+		planIDs = append(planIDs, plan.PlanID)
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// メニュー名を含めて取得し直す
+	var plans []MealPlan
+	err := u.conn.Select(mealPlanColumns).Where("plan_id IN ?", planIDs).Order("plan_id").Find(&plans).Error
+	return plans, err
+}
+
+// GetMealPlans はユーザーの指定期間（両端を含む）の献立を日付・時間帯順に取得します
+func (u UserDriverImpl) GetMealPlans(userID uint, from, to time.Time) ([]MealPlan, error) {
+	var plans []MealPlan
+
+	err := u.conn.Select(mealPlanColumns).
+		Where("user_id = ? AND planned_on BETWEEN ? AND ?", userID, from, to).
+		Order("planned_on").
+		Order("FIELD(meal_slot, 'breakfast', 'lunch', 'dinner')").
+		Find(&plans).Error
+	return plans, err
+}
+
+// GetMealPlanByID は献立IDで献立を取得します
+func (u UserDriverImpl) GetMealPlanByID(planID uint) (MealPlan, error) {
+	var plan MealPlan
+	err := u.conn.Select(mealPlanColumns).First(&plan, planID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return MealPlan{}, domain.NewNotFound("meal_plan_not_found", "meal plan not found").Wrap(err)
+	}
+	return plan, err
+}
+
+// MoveMealPlan は献立を別の日付・時間帯に移動します
+func (u UserDriverImpl) MoveMealPlan(planID uint, plannedOn time.Time, mealSlot string) (MealPlan, error) {
+	plan, err := u.GetMealPlanByID(planID)
+	if err != nil {
+		return MealPlan{}, err
+	}
+
+	// トランザクション開始
+	tx := u.conn.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 移動先の空きチェック（自分自身は除く）
+	if err := ensureMealSlotAvailable(tx, plan.UserID, plannedOn, mealSlot, planID); err != nil {
+		tx.Rollback()
+		return MealPlan{}, err
+	}
+
+	if err := tx.Model(&MealPlan{PlanID: planID}).Updates(MealPlan{PlannedOn: plannedOn, MealSlot: mealSlot}).Error; err != nil {
+		tx.Rollback()
+		// 空きチェック後に移動先へ同時に追加された場合
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return MealPlan{}, mealSlotTaken(plannedOn, mealSlot).Wrap(err)
+		}
+		return MealPlan{}, err
+	}
+
+	// コミット
+	if err := tx.Commit().Error; err != nil {
+		return MealPlan{}, err
+	}
+
+	return u.GetMealPlanByID(planID)
+}
+
+// RemoveMealPlanByID は献立IDで献立を削除します
+func (u UserDriverImpl) RemoveMealPlanByID(planID uint) error {
+	return u.conn.Delete(&MealPlan{}, planID).Error
+}
+
+// ensureMealSlotAvailable は指定した日付・時間帯に献立がないことを確認します
+// excludePlanIDの献立は対象外とします（0の場合はすべてを対象とします）
+func ensureMealSlotAvailable(tx *gorm.DB, userID uint, plannedOn time.Time, mealSlot string, excludePlanID uint) error {
+	query := tx.Model(&MealPlan{}).Where("user_id = ? AND planned_on = ? AND meal_slot = ?", userID, plannedOn, mealSlot)
+	if excludePlanID != 0 {
+		query = query.Where("plan_id <> ?", excludePlanID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return mealSlotTaken(plannedOn, mealSlot)
+	}
+
+	return nil
+}
+
+// mealSlotTaken は指定した日付・時間帯に既に献立があることを表すエラーを返します
+func mealSlotTaken(plannedOn time.Time, mealSlot string) *domain.Error {
+	return domain.NewConflict("meal_slot_taken", "a meal is already planned for "+plannedOn.Format(domain.DateLayout)+" "+mealSlot)
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	"errors"
+	"go-menu/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Suggestion はユーザーに提案したメニューと採用状況のためのsuggestionsテーブルを表します
+type Suggestion struct {
+	SuggestionID uint       `gorm:"primaryKey;column:suggestion_id" json:"suggestion_id"`
+	UserID       uint       `gorm:"not null;column:user_id;index:idx_suggestion_user_accepted,priority:1" json:"user_id"`
+	MenuID       uint       `gorm:"not null;column:menu_id;index" json:"menu_id"`
+	SuggestedAt  time.Time  `gorm:"not null;column:suggested_at" json:"suggested_at"`
+	AcceptedAt   *time.Time `gorm:"column:accepted_at;index:idx_suggestion_user_accepted,priority:2" json:"accepted_at"`
+}
+
+func (Suggestion) TableName() string {
+	return "suggestions"
+}
+
+// RecordSuggestions は提案したメニューを記録します
+func (u UserDriverImpl) RecordSuggestions(userID uint, menuIDs []uint, suggestedAt time.Time) ([]Suggestion, error) {
+	suggestions := []Suggestion{}
+	if len(menuIDs) == 0 {
+		return suggestions, nil
+	}
+
+	for _, menuID := range menuIDs {
+		suggestions = append(suggestions, Suggestion{
+			UserID:      userID,
+			MenuID:      menuID,
+			SuggestedAt: suggestedAt,
+		})
+	}
+
+	err := u.conn.Create(&suggestions).Error
+	return suggestions, err
+}
+
+// GetSuggestionByID は提案IDで提案を取得します
+func (u UserDriverImpl) GetSuggestionByID(suggestionID uint) (Suggestion, error) {
+	var suggestion Suggestion
+	err := u.conn.First(&suggestion, suggestionID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Suggestion{}, domain.NewNotFound("suggestion_not_found", "suggestion not found").Wrap(err)
+	}
+	return suggestion, err
+}
+
+// AcceptSuggestion は提案を採用済みにします
+func (u UserDriverImpl) AcceptSuggestion(suggestionID uint, acceptedAt time.Time) (Suggestion, error) {
+	// 未採用の場合のみ更新することで、二重の採用を防ぐ
+	result := u.conn.Model(&Suggestion{}).
+		Where("suggestion_id = ? AND accepted_at IS NULL", suggestionID).
+		Update("accepted_at", acceptedAt)
+	if result.Error != nil {
+		return Suggestion{}, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return Suggestion{}, domain.NewConflict("suggestion_already_accepted", "suggestion is already accepted")
+	}
+
+	return u.GetSuggestionByID(suggestionID)
+}
+
+// GetAcceptedSuggestions はユーザーが指定日時以降に採用した提案を新しい順に取得します
+func (u UserDriverImpl) GetAcceptedSuggestions(userID uint, since time.Time) ([]Suggestion, error) {
+	var suggestions []Suggestion
+	err := u.conn.
+		Where("user_id = ? AND accepted_at >= ?", userID, since).
+		Order("accepted_at DESC").
+		Find(&suggestions).Error
+	return suggestions, err
+}
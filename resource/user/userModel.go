@@ -2,45 +2,102 @@ package user
 
 import (
 	"errors"
+	"go-menu/domain"
+	"go-menu/resource/menu"
 	"time"
 
 	"gorm.io/gorm"
 )
 
-// User はAuth0統合のためのusersテーブルを表します
+// DefaultIssuer はAuth0の発行者名（発行者の列を追加する前のユーザーはAuth0のユーザーとして扱う）
+const DefaultIssuer = "auth0"
+
+// LegacySubjectIndex は発行者ごとに一意にする前のauth0_subのユニークインデックス
+const LegacySubjectIndex = "idx_users_auth0_sub"
+
+// User はIDプロバイダー統合のためのusersテーブルを表します
+// 同じsubでも発行者が異なる場合は別のユーザーとして扱います
 type User struct {
-	UserID    uint      `gorm:"primaryKey;column:user_id" json:"user_id"`
-	Auth0Sub  string    `gorm:"type:varchar(255);uniqueIndex;not null;column:auth0_sub" json:"auth0_sub"`
+	UserID uint `gorm:"primaryKey;column:user_id" json:"user_id"`
+	// トークンの発行者名（認証設定の発行者名）
+	Issuer   string `gorm:"type:varchar(64);not null;default:auth0;uniqueIndex:idx_users_issuer_sub,priority:1;column:issuer" json:"issuer"`
+	Auth0Sub string `gorm:"type:varchar(255);not null;uniqueIndex:idx_users_issuer_sub,priority:2;column:auth0_sub" json:"auth0_sub"`
+	// トークンのクレームから取得したプロフィール
+	Email string `gorm:"type:varchar(255);column:email" json:"email"`
+	Name  string `gorm:"type:varchar(255);column:name" json:"name"`
+	// 権限（admin, editor, viewer）。トークンのクレームの権限の方が強い場合はそちらを使う
+	Role      string    `gorm:"type:varchar(16);not null;default:viewer;column:role" json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 
 	// リレーション
-	Favorites []Favorite `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Favorites     []Favorite     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	MealHistories []MealHistory  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Suggestions   []Suggestion   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	MealPlans     []MealPlan     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	CalendarToken *CalendarToken `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Allergens     []UserAllergen `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Diets         []UserDiet     `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
 }
 
 // Favorite はユーザーのお気に入りメニューのためのfavoritesテーブルを表します
 type Favorite struct {
 	FavoriteID uint      `gorm:"primaryKey;column:favorite_id" json:"favorite_id"`
-	UserID     uint      `gorm:"not null;column:user_id;index" json:"user_id"`
-	MenuID     uint      `gorm:"not null;column:menu_id;index" json:"menu_id"`
+	UserID     uint      `gorm:"not null;column:user_id;uniqueIndex:idx_favorites_user_menu,priority:1" json:"user_id"`
+	MenuID     uint      `gorm:"not null;column:menu_id;index;uniqueIndex:idx_favorites_user_menu,priority:2" json:"menu_id"`
 	CreatedAt  time.Time `json:"created_at"`
 
 	// リレーション
 	User User `gorm:"references:UserID" json:"user"`
+	// メニューを物理削除した場合はお気に入りも削除する
+	// menu_listは既存のテーブルのため、AutoMigrateの対象に含めず外部キー制約のみ作成する（FavoriteMenuConstraint）
+	Menu menu.Menu `gorm:"foreignKey:MenuID;references:MenuId;constraint:OnDelete:CASCADE;-:migration" json:"-"`
 }
 
+// FavoriteMenuConstraint はfavoritesからmenu_listへの外部キー制約のフィールド名
+const FavoriteMenuConstraint = "Menu"
+
+// FavoriteUniqueIndex はユーザーとメニューの組み合わせを一意にするfavoritesのユニークインデックス
+const FavoriteUniqueIndex = "idx_favorites_user_menu"
+
 func (Favorite) TableName() string {
 	return "favorites"
 }
 
+// UserProfile はトークンのクレームから取得したユーザーのプロフィール
+type UserProfile struct {
+	Email string
+	Name  string
+}
+
 // UserDriver はユーザー関連のデータベース操作のためのインターフェース
 type UserDriver interface {
-	CreateOrGetUser(auth0Sub string) (User, bool, error)
-	GetUserByAuth0Sub(auth0Sub string) (User, error)
+	CreateOrGetUser(issuer, subject string, profile UserProfile) (User, bool, error)
+	GetUserBySubject(issuer, subject string) (User, error)
+	UpdateUserRole(userID uint, role string) (User, error)
 	AddFavorite(userID, menuID uint) (Favorite, error)
 	GetUserFavorites(userID uint) ([]Favorite, error)
+	FindFavorites(query FavoriteQuery) ([]Favorite, bool, int64, error)
 	GetFavoriteByID(favoriteID uint) (Favorite, error)
 	RemoveFavoriteByID(favoriteID uint) error
+	AddMealHistory(userID, menuID uint, eatenOn time.Time, mealSlot string) (MealHistory, error)
+	GetMealHistories(userID uint, from, to time.Time) ([]MealHistory, error)
+	GetMealHistoryByID(historyID uint) (MealHistory, error)
+	RemoveMealHistoryByID(historyID uint) error
+	AddMealPlans(userID uint, entries []MealPlanEntry) ([]MealPlan, error)
+	GetMealPlans(userID uint, from, to time.Time) ([]MealPlan, error)
+	GetMealPlanByID(planID uint) (MealPlan, error)
+	MoveMealPlan(planID uint, plannedOn time.Time, mealSlot string) (MealPlan, error)
+	RemoveMealPlanByID(planID uint) error
+	SaveCalendarToken(userID uint, tokenHash string) (CalendarToken, error)
+	DeleteCalendarToken(userID uint) error
+	GetCalendarTokenByHash(tokenHash string) (CalendarToken, error)
+	RecordSuggestions(userID uint, menuIDs []uint, suggestedAt time.Time) ([]Suggestion, error)
+	GetSuggestionByID(suggestionID uint) (Suggestion, error)
+	AcceptSuggestion(suggestionID uint, acceptedAt time.Time) (Suggestion, error)
+	GetAcceptedSuggestions(userID uint, since time.Time) ([]Suggestion, error)
+	GetDietaryProfile(userID uint) (DietaryProfile, error)
+	UpdateDietaryProfile(userID uint, allergens []string, diets []string) (DietaryProfile, error)
 }
 
 // UserDriverImpl はUserDriverインターフェースを実装します
@@ -53,69 +110,116 @@ func ProvideUserDriver(conn *gorm.DB) UserDriver {
 	return UserDriverImpl{conn: conn}
 }
 
-// CreateOrGetUser は新しいユーザーを作成するか、Auth0Subで既存のユーザーを返します
+// CreateOrGetUser は新しいユーザーを作成するか、発行者とSubjectで既存のユーザーを返します
+// 既存のユーザーのプロフィールは、空でない値が変わっている場合に更新します
 // 戻り値: (User, bool, error) - boolは新規作成の場合true
-func (u UserDriverImpl) CreateOrGetUser(auth0Sub string) (User, bool, error) {
+func (u UserDriverImpl) CreateOrGetUser(issuer, subject string, profile UserProfile) (User, bool, error) {
 	var user User
 
 	// 最初に既存のユーザーを検索
-	err := u.conn.Where("auth0_sub = ?", auth0Sub).First(&user).Error
+	err := u.conn.Where("issuer = ? AND auth0_sub = ?", issuer, subject).First(&user).Error
 	if err == nil {
 		// ユーザーは既に存在します
-		return user, false, nil
+		return u.updateProfile(user, profile)
 	}
 
-	if err != gorm.ErrRecordNotFound {
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
 		// その他のエラーが発生しました
 		return User{}, false, err
 	}
 
 	// ユーザーが存在しないため、新しいユーザーを作成
-	user = User{Auth0Sub: auth0Sub}
-	if err := u.conn.Create(&user).Error; err != nil {
+	user = User{Issuer: issuer, Auth0Sub: subject, Email: profile.Email, Name: profile.Name}
+	err = u.conn.Create(&user).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		// 同時に作成された場合は作成済みのユーザーを返す
+		if err := u.conn.Where("issuer = ? AND auth0_sub = ?", issuer, subject).First(&user).Error; err != nil {
+			return User{}, false, err
+		}
+		return u.updateProfile(user, profile)
+	}
+	if err != nil {
 		return User{}, false, err
 	}
 
 	return user, true, nil
 }
 
-// GetUserByAuth0Sub はAuth0 Subjectでユーザーを取得します
-func (u UserDriverImpl) GetUserByAuth0Sub(auth0Sub string) (User, error) {
+// updateProfile はプロフィールのうち空でない値が変わっている項目を更新します
+func (u UserDriverImpl) updateProfile(user User, profile UserProfile) (User, bool, error) {
+	updated := user
+	if profile.Email != "" {
+		updated.Email = profile.Email
+	}
+	if profile.Name != "" {
+		updated.Name = profile.Name
+	}
+	if updated.Email == user.Email && updated.Name == user.Name {
+		return user, false, nil
+	}
+
+	user = updated
+	if err := u.conn.Model(&user).Updates(User{Email: user.Email, Name: user.Name}).Error; err != nil {
+		return User{}, false, err
+	}
+
+	return user, false, nil
+}
+
+// GetUserBySubject は発行者とSubjectでユーザーを取得します
+func (u UserDriverImpl) GetUserBySubject(issuer, subject string) (User, error) {
 	var user User
-	err := u.conn.Where("auth0_sub = ?", auth0Sub).First(&user).Error
+	err := u.conn.Where("issuer = ? AND auth0_sub = ?", issuer, subject).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return User{}, domain.NewNotFound("user_not_found", "user not found").Wrap(err)
+	}
 	return user, err
 }
 
-// AddFavorite はメニューをユーザーのお気に入りに追加します
-func (u UserDriverImpl) AddFavorite(userID, menuID uint) (Favorite, error) {
-	// 重複チェック：既にお気に入りに追加されているかを確認
-	var existingFavorite Favorite
-	err := u.conn.Where("user_id = ? AND menu_id = ?", userID, menuID).First(&existingFavorite).Error
-	if err == nil {
-		// 既に存在している場合は重複エラーを返す
-		return Favorite{}, errors.New("menu is already in favorites")
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-		// その他のデータベースエラー
-		return Favorite{}, err
+// UpdateUserRole はユーザーの権限を更新します
+func (u UserDriverImpl) UpdateUserRole(userID uint, role string) (User, error) {
+	var user User
+	if err := u.conn.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return User{}, domain.NewNotFound("user_not_found", "user not found").Wrap(err)
+		}
+		return User{}, err
 	}
 
-	// メニュー存在チェック：メニューテーブルにmenu_idが存在するかを確認
+	if err := u.conn.Model(&user).Update("role", role).Error; err != nil {
+		return User{}, err
+	}
+	user.Role = role
+
+	return user, nil
+}
+
+// AddFavorite はメニューをユーザーのお気に入りに追加します
+func (u UserDriverImpl) AddFavorite(userID, menuID uint) (Favorite, error) {
+	// 論理削除したメニューは外部キー制約では検出できないため、事前に確認する
 	var menuCount int64
-	err = u.conn.Table("menu_list").Where("menu_id = ?", menuID).Count(&menuCount).Error
+	err := u.conn.Table("menu_list").Where("menu_id = ? AND deleted_at IS NULL", menuID).Count(&menuCount).Error
 	if err != nil {
 		return Favorite{}, err
 	}
 	if menuCount == 0 {
-		return Favorite{}, errors.New("menu not found")
+		return Favorite{}, domain.NewNotFound("menu_not_found", "menu not found")
 	}
 
 	// お気に入りを作成
+	// 重複はユニークインデックス、メニューの物理削除は外部キー制約で検出する
 	favorite := Favorite{
 		UserID: userID,
 		MenuID: menuID,
 	}
 
 	err = u.conn.Create(&favorite).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return Favorite{}, domain.NewConflict("favorite_already_exists", "menu is already in favorites").Wrap(err)
+	}
+	if errors.Is(err, gorm.ErrForeignKeyViolated) {
+		return Favorite{}, domain.NewNotFound("menu_not_found", "menu not found").Wrap(err)
+	}
 	return favorite, err
 }
 
@@ -130,6 +234,9 @@ func (u UserDriverImpl) GetUserFavorites(userID uint) ([]Favorite, error) {
 func (u UserDriverImpl) GetFavoriteByID(favoriteID uint) (Favorite, error) {
 	var favorite Favorite
 	err := u.conn.First(&favorite, favoriteID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Favorite{}, domain.NewNotFound("favorite_not_found", "favorite not found").Wrap(err)
+	}
 	return favorite, err
 }
 
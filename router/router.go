@@ -1,8 +1,11 @@
 package router
 
 import (
+	"go-menu/devauth"
 	"go-menu/di"
+	"go-menu/domain"
 	"go-menu/middleware"
+	"log"
 
 	"time"
 
@@ -11,7 +14,9 @@ import (
 )
 
 func NewServer() *gin.Engine {
-	r := gin.Default()
+	// gin.Defaultのロガーはカレンダー購読トークンを含むURLをそのまま出力するため、置き換えたロガーを使う
+	r := gin.New()
+	r.Use(middleware.RequestLogger(), gin.Recovery())
 	r.Use(cors.New(cors.Config{
 		// アクセスを許可したいアクセス元
 		AllowOrigins: []string{"*"},
@@ -51,6 +56,8 @@ func NewServer() *gin.Engine {
 		// preflightリクエストの結果をキャッシュする時間
 		MaxAge: 24 * time.Hour,
 	}))
+	// ハンドラーで登録されたエラーをRFC 7807形式で返す
+	r.Use(middleware.ErrorHandler())
 
 	v1 := r.Group("/v1")
 
@@ -60,30 +67,109 @@ func NewServer() *gin.Engine {
 		v1.GET("/ping", systemHandler.Ping)
 	}
 
-	// メニュー関連エンドポイント（認証不要）
+	// 認証設定とミドルウェアの初期化
+	authConfig := middleware.NewAuthConfig()
+	userDriver := di.InitUserDriver()
+	// 発行者ごとの公開鍵はキャッシュし、バックグラウンドで更新する
+	tokenVerifier := middleware.NewTokenVerifier(authConfig)
+
+	// 開発用認証（DEV_AUTH_ENABLED=trueの場合のみ）
+	// ローカルの署名鍵で発行したトークンを受け付け、公開鍵を自身で提供する
+	devAuthConfig := devauth.NewConfig()
+	if devAuthConfig.Enabled {
+		devAuth, err := devauth.Load(devAuthConfig)
+		if err != nil {
+			log.Fatal("開発用認証の署名鍵の読み込みに失敗しました: ", err)
+		}
+		log.Println("開発用認証が有効です。本番環境では無効にしてください: ", devAuthConfig.Issuer)
+		tokenVerifier.AddIssuer(devAuth.IssuerConfig(), devAuth)
+
+		devAuthHandler := di.InitDevAuthHandler(devAuth)
+		v1.GET("/dev/.well-known/openid-configuration", devAuthHandler.GetDiscovery)
+		v1.GET("/dev/jwks.json", devAuthHandler.GetJWKS)
+	}
+	tokenVerifier.Start()
+	authMiddleware := middleware.AuthMiddleware(userDriver, tokenVerifier, authConfig)
+	optionalAuthMiddleware := middleware.OptionalAuthMiddleware(userDriver, tokenVerifier, authConfig)
+	registrationAuthMiddleware := middleware.RegistrationAuthMiddleware(tokenVerifier)
+	// 編集者・管理者のみ許可する操作
+	requireEditor := middleware.RequireRole(domain.RoleEditor, domain.RoleAdmin)
+	requireAdmin := middleware.RequireRole(domain.RoleAdmin)
+
+	// メニュー関連エンドポイント
 	{
 		menuHandler := di.InitTodoHandler()
-		v1.GET("/menus", menuHandler.GetAll)
-		v1.POST("/menus", menuHandler.CreateMenu)
-		v1.PUT("/menus/:menu_id", menuHandler.UpdateMenu)
-		v1.DELETE("/menus/:menu_id", menuHandler.DeleteMenu)
-		v1.PATCH("/menus/:menu_id/genres", menuHandler.UpdateGenreRelations)
-		v1.PATCH("/menus/:menu_id/categories", menuHandler.UpdateCategoryRelations)
+
+		// 参照（認証不要）
+		// ログイン時は食事制限プロファイルに反するメニューを除外する
+		// 管理者はinclude_deleted=trueで論理削除したメニューも取得できる
+		v1.GET("/menus", optionalAuthMiddleware, menuHandler.GetAll)
+		// ログイン時はお気に入りの重み付けと食事制限による除外を行う
+		v1.GET("/menus/random", optionalAuthMiddleware, menuHandler.PickRandomMenus)
+		// カタログ全体のバックアップ・環境間の比較用
+		v1.GET("/menus/export", menuHandler.ExportMenus)
+		v1.GET("/menus/:menu_id", menuHandler.GetMenu)
+		v1.GET("/menus/:menu_id/recipe", menuHandler.GetRecipe)
+
+		// 更新（編集者・管理者のみ）
+		editorGroup := v1.Group("/menus", authMiddleware, requireEditor)
+		{
+			editorGroup.POST("", menuHandler.CreateMenu)
+			// CSV（UTF-8 / Shift_JIS）またはJSON配列からの一括登録
+			editorGroup.POST("/import", menuHandler.ImportMenus)
+			editorGroup.PUT("/:menu_id", menuHandler.UpdateMenu)
+			editorGroup.DELETE("/:menu_id", menuHandler.DeleteMenu)
+			editorGroup.PATCH("/:menu_id/genres", menuHandler.UpdateGenreRelations)
+			editorGroup.PATCH("/:menu_id/categories", menuHandler.UpdateCategoryRelations)
+			editorGroup.PATCH("/:menu_id/allergens", menuHandler.UpdateAllergens)
+			editorGroup.PATCH("/:menu_id/diets", menuHandler.UpdateDiets)
+			editorGroup.PUT("/:menu_id/recipe", menuHandler.UpdateRecipe)
+		}
+
+		// 論理削除したメニューの復元（管理者のみ）
+		v1.POST("/menus/:menu_id/restore", authMiddleware, requireAdmin, menuHandler.RestoreMenu)
 	}
 
-	// ユーザー関連エンドポイント（認証不要）
+	// ジャンル関連エンドポイント（参照は認証不要、更新は編集者・管理者のみ）
+	{
+		genreHandler := di.InitGenreHandler()
+		v1.GET("/genres", genreHandler.GetAll)
+
+		editorGroup := v1.Group("/genres", authMiddleware, requireEditor)
+		{
+			editorGroup.POST("", genreHandler.CreateGenre)
+			editorGroup.PUT("/:genre_id", genreHandler.UpdateGenre)
+			editorGroup.DELETE("/:genre_id", genreHandler.DeleteGenre)
+		}
+	}
+
+	// カテゴリ関連エンドポイント（参照は認証不要、更新は編集者・管理者のみ）
+	{
+		categoryHandler := di.InitCategoryHandler()
+		v1.GET("/categories", categoryHandler.GetAll)
+
+		editorGroup := v1.Group("/categories", authMiddleware, requireEditor)
+		{
+			editorGroup.POST("", categoryHandler.CreateCategory)
+			editorGroup.PUT("/:category_id", categoryHandler.UpdateCategory)
+			editorGroup.DELETE("/:category_id", categoryHandler.DeleteCategory)
+		}
+	}
+
+	// 買い物リスト関連エンドポイント（認証不要）
+	{
+		shoppingListHandler := di.InitShoppingListHandler()
+		v1.POST("/shopping-list", shoppingListHandler.CreateShoppingList)
+	}
+
+	// ユーザー関連エンドポイント（トークンの検証のみ、ユーザーの登録は不要）
 	{
 		userHandler := di.InitUserHandler()
-		v1.POST("/users", userHandler.CreateUser)
+		v1.POST("/users", registrationAuthMiddleware, userHandler.CreateUser)
 	}
 
 	// お気に入り関連エンドポイント（認証必要）
 	{
-		// Auth0設定とミドルウェアの初期化
-		auth0Config := middleware.NewAuth0Config()
-		userDriver := di.InitUserDriver()
-		authMiddleware := middleware.AuthMiddleware(userDriver, auth0Config)
-
 		favoriteHandler := di.InitFavoriteHandler()
 
 		// 認証が必要なエンドポイントグループ
@@ -96,5 +182,74 @@ func NewServer() *gin.Engine {
 		}
 	}
 
+	// 食事制限プロファイル関連エンドポイント（認証必要）
+	{
+		profileHandler := di.InitProfileHandler()
+
+		profileGroup := v1.Group("/profile")
+		profileGroup.Use(authMiddleware)
+		{
+			profileGroup.GET("/dietary", profileHandler.GetDietaryProfile)
+			profileGroup.PUT("/dietary", profileHandler.UpdateDietaryProfile)
+		}
+	}
+
+	// 食事履歴関連エンドポイント（認証必要）
+	{
+		historyHandler := di.InitHistoryHandler()
+
+		historyGroup := v1.Group("/history")
+		historyGroup.Use(authMiddleware)
+		{
+			historyGroup.GET("", historyHandler.GetHistories)
+			historyGroup.POST("", historyHandler.AddHistory)
+			historyGroup.DELETE("/:historyId", historyHandler.RemoveHistoryByID)
+		}
+	}
+
+	// 献立関連エンドポイント（認証必要）
+	{
+		mealPlanHandler := di.InitMealPlanHandler()
+
+		planGroup := v1.Group("/plans")
+		planGroup.Use(authMiddleware)
+		{
+			planGroup.GET("", mealPlanHandler.GetMealPlans)
+			planGroup.POST("", mealPlanHandler.AddMealPlan)
+			planGroup.POST("/auto-fill", mealPlanHandler.AutoFillWeek)
+			planGroup.PATCH("/:planId", mealPlanHandler.MoveMealPlan)
+			planGroup.DELETE("/:planId", mealPlanHandler.RemoveMealPlanByID)
+		}
+	}
+
+	// カレンダー購読関連エンドポイント
+	{
+		calendarHandler := di.InitCalendarHandler()
+
+		// カレンダーアプリ向けのため、URLの秘密トークンで認証する
+		v1.GET("/calendar/:feed", calendarHandler.GetFeed)
+		// 発行済みの購読URLのため残す
+		v1.GET("/calendar.ics", calendarHandler.GetLegacyFeed)
+
+		calendarGroup := v1.Group("/calendar")
+		calendarGroup.Use(authMiddleware)
+		{
+			calendarGroup.POST("/token", calendarHandler.IssueToken)
+			calendarGroup.DELETE("/token", calendarHandler.RevokeToken)
+		}
+	}
+
+	// メニュー提案関連エンドポイント（認証必要）
+	{
+		suggestionHandler := di.InitSuggestionHandler()
+
+		suggestionGroup := v1.Group("/suggestions")
+		suggestionGroup.Use(authMiddleware)
+		{
+			suggestionGroup.POST("", suggestionHandler.Suggest)
+			suggestionGroup.POST("/:suggestionId/accept", suggestionHandler.Accept)
+		}
+	}
+
 	return r
 }
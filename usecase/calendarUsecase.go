@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"go-menu/domain"
+	"go-menu/usecase/port"
+	"time"
+)
+
+const (
+	// カレンダーに含める過去の日数
+	calendarPastDays = 30
+	// カレンダーに含める未来の日数
+	calendarFutureDays = 90
+	// カレンダートークンのバイト数
+	calendarTokenBytes = 32
+)
+
+type CalendarUsecase struct {
+	mealPlanPort port.MealPlanPort
+	tokenPort    port.CalendarTokenPort
+}
+
+func ProvideCalendarUsecase(mealPlanPort port.MealPlanPort, tokenPort port.CalendarTokenPort) CalendarUsecase {
+	return CalendarUsecase{mealPlanPort, tokenPort}
+}
+
+// IssueToken はカレンダー購読用の秘密トークンを発行する
+// 既存のトークンは無効になる。トークンは保存しないため、発行時にのみ返す
+func (u CalendarUsecase) IssueToken(userId uint) (string, error) {
+	buf := make([]byte, calendarTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := u.tokenPort.SaveCalendarToken(userId, HashCalendarToken(token)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RevokeToken はカレンダー購読用の秘密トークンを無効にする
+func (u CalendarUsecase) RevokeToken(userId uint) error {
+	return u.tokenPort.DeleteCalendarToken(userId)
+}
+
+// GetScheduledMenus はトークンの持ち主の献立を取得する
+// 削除されたメニューの献立は含めない
+func (u CalendarUsecase) GetScheduledMenus(token string) ([]domain.MealPlan, error) {
+	userId, err := u.tokenPort.FindUserIdByCalendarToken(HashCalendarToken(token))
+	if domain.KindOf(err) == domain.KindNotFound {
+		return nil, domain.NewUnauthorized("invalid_calendar_token", "invalid calendar token").Wrap(err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today.AddDate(0, 0, -calendarPastDays)
+	to := today.AddDate(0, 0, calendarFutureDays)
+	plans, err := u.mealPlanPort.GetMealPlans(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := []domain.MealPlan{}
+	for _, plan := range plans {
+		if plan.MenuExists {
+			scheduled = append(scheduled, plan)
+		}
+	}
+
+	return scheduled, nil
+}
+
+// hashCalendarToken はトークンを保存・照合するためのハッシュを返す
+func HashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+)
+
+type CategoryUsecase struct {
+	categoryPort port.CategoryPort
+}
+
+func ProvideCategoryUsecase(categoryPort port.CategoryPort) CategoryUsecase {
+	return CategoryUsecase{categoryPort}
+}
+
+func (u CategoryUsecase) GetAll() ([]domain.Category, error) {
+	categories, err := u.categoryPort.GetAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+func (u CategoryUsecase) CreateCategory(category domain.Category) (domain.Category, error) {
+	category, err := u.categoryPort.CreateCategory(category)
+
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	return category, nil
+}
+
+func (u CategoryUsecase) UpdateCategory(category domain.Category) (domain.Category, error) {
+	category, err := u.categoryPort.UpdateCategory(category)
+
+	if err != nil {
+		return domain.Category{}, err
+	}
+
+	return category, nil
+}
+
+func (u CategoryUsecase) DeleteCategory(categoryId uint, cascade bool) error {
+	err := u.categoryPort.DeleteCategory(categoryId, cascade)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
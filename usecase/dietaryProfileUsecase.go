@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+)
+
+type DietaryProfileUsecase struct {
+	profilePort port.DietaryProfilePort
+}
+
+func ProvideDietaryProfileUsecase(profilePort port.DietaryProfilePort) DietaryProfileUsecase {
+	return DietaryProfileUsecase{profilePort}
+}
+
+func (u DietaryProfileUsecase) GetDietaryProfile(userId uint) (domain.DietaryProfile, error) {
+	profile, err := u.profilePort.GetDietaryProfile(userId)
+
+	if err != nil {
+		return domain.DietaryProfile{}, err
+	}
+
+	return profile, nil
+}
+
+func (u DietaryProfileUsecase) UpdateDietaryProfile(userId uint, profile domain.DietaryProfile) (domain.DietaryProfile, error) {
+	var fields []domain.FieldError
+	fields = append(fields, validateTags("allergens", profile.Allergens, domain.Allergens)...)
+	fields = append(fields, validateTags("diets", profile.Diets, domain.Diets)...)
+	if err := toValidationError(fields); err != nil {
+		return domain.DietaryProfile{}, err
+	}
+
+	profile, err := u.profilePort.UpdateDietaryProfile(userId, profile)
+
+	if err != nil {
+		return domain.DietaryProfile{}, err
+	}
+
+	return profile, nil
+}
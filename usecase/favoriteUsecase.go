@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+)
+
+type FavoriteUsecase struct {
+	favoritePort port.FavoritePort
+}
+
+func ProvideFavoriteUsecase(favoritePort port.FavoritePort) FavoriteUsecase {
+	return FavoriteUsecase{favoritePort}
+}
+
+// FindFavorites はユーザーのお気に入りをメニューとともに取得する
+// 食事制限プロファイルに反するメニューのお気に入りは含めない
+func (u FavoriteUsecase) FindFavorites(query domain.FavoriteQuery) (domain.FavoritePage, error) {
+	page, err := u.favoritePort.FindFavorites(query)
+
+	if err != nil {
+		return domain.FavoritePage{}, err
+	}
+
+	return page, nil
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+)
+
+type GenreUsecase struct {
+	genrePort port.GenrePort
+}
+
+func ProvideGenreUsecase(genrePort port.GenrePort) GenreUsecase {
+	return GenreUsecase{genrePort}
+}
+
+func (u GenreUsecase) GetAll() ([]domain.Genre, error) {
+	genres, err := u.genrePort.GetAll()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+func (u GenreUsecase) CreateGenre(genre domain.Genre) (domain.Genre, error) {
+	genre, err := u.genrePort.CreateGenre(genre)
+
+	if err != nil {
+		return domain.Genre{}, err
+	}
+
+	return genre, nil
+}
+
+func (u GenreUsecase) UpdateGenre(genre domain.Genre) (domain.Genre, error) {
+	genre, err := u.genrePort.UpdateGenre(genre)
+
+	if err != nil {
+		return domain.Genre{}, err
+	}
+
+	return genre, nil
+}
+
+func (u GenreUsecase) DeleteGenre(genreId uint, cascade bool) error {
+	err := u.genrePort.DeleteGenre(genreId, cascade)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
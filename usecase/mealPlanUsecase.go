@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+	"math/rand/v2"
+	"time"
+)
+
+// 自動入力でお気に入りのメニューを選びやすくする倍率
+const autoFillFavoriteWeight = 3
+
+type MealPlanUsecase struct {
+	menuPort     port.MenuPort
+	mealPlanPort port.MealPlanPort
+	favoritePort port.FavoritePort
+	profilePort  port.DietaryProfilePort
+}
+
+func ProvideMealPlanUsecase(menuPort port.MenuPort, mealPlanPort port.MealPlanPort, favoritePort port.FavoritePort, profilePort port.DietaryProfilePort) MealPlanUsecase {
+	return MealPlanUsecase{menuPort, mealPlanPort, favoritePort, profilePort}
+}
+
+// GetMealPlans は指定期間（両端を含む）の献立を取得する
+// fromがゼロ値の場合は今週の月曜日から、toがゼロ値の場合はfromから7日間を対象とする
+func (u MealPlanUsecase) GetMealPlans(userId uint, from, to time.Time) ([]domain.MealPlan, error) {
+	if from.IsZero() {
+		from, _ = weekRange(time.Now())
+	}
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 6)
+	}
+
+	plans, err := u.mealPlanPort.GetMealPlans(userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// AddMealPlan は献立を追加する
+func (u MealPlanUsecase) AddMealPlan(userId uint, entry domain.MealPlanEntry) (domain.MealPlan, error) {
+	plans, err := u.mealPlanPort.AddMealPlans(userId, []domain.MealPlanEntry{entry})
+	if err != nil {
+		return domain.MealPlan{}, err
+	}
+
+	return plans[0], nil
+}
+
+// MoveMealPlan は自分の献立を別の日付・時間帯に移動する
+func (u MealPlanUsecase) MoveMealPlan(userId uint, planId uint, plannedOn time.Time, mealSlot string) (domain.MealPlan, error) {
+	if _, err := u.getOwnMealPlan(userId, planId); err != nil {
+		return domain.MealPlan{}, err
+	}
+
+	return u.mealPlanPort.MoveMealPlan(planId, plannedOn, mealSlot)
+}
+
+// RemoveMealPlan は自分の献立を削除する
+func (u MealPlanUsecase) RemoveMealPlan(userId uint, planId uint) error {
+	if _, err := u.getOwnMealPlan(userId, planId); err != nil {
+		return err
+	}
+
+	return u.mealPlanPort.RemoveMealPlan(planId)
+}
+
+// AutoFillWeek は指定日を含む週（月曜〜日曜）の空いている時間帯に献立を入れる
+// お気に入りを選びやすくし、その週に多く登場しているジャンルは選びにくくする
+// 同じシードと条件であれば同じ結果を返す
+func (u MealPlanUsecase) AutoFillWeek(userId uint, day time.Time, slots []string, seed int64) ([]domain.MealPlan, error) {
+	start, end := weekRange(day)
+
+	plans, err := u.mealPlanPort.GetMealPlans(userId, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := u.profilePort.GetDietaryProfile(userId)
+	if err != nil {
+		return nil, err
+	}
+	favoriteIds, err := u.favoritePort.GetFavoriteMenuIds(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 食事制限プロファイルに反しないメニューが候補（お気に入りは候補の上限に関わらず含める）
+	candidates, err := u.menuPort.FindCandidates(domain.MenuCandidateQuery{
+		ExcludeAllergens: profile.Allergens,
+		RequireDiets:     profile.Diets,
+		PreferIds:        favoriteIds,
+		Limit:            maxRandomCandidates,
+		Seed:             seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return plans, nil
+	}
+	sortMenusById(candidates)
+
+	// 既存の献立のメニューのジャンルを取得する（候補に含まれないメニューもある）
+	plannedIds := []uint{}
+	for _, plan := range plans {
+		plannedIds = append(plannedIds, plan.MenuID)
+	}
+	plannedMenus, err := u.menuPort.GetMenusByIds(plannedIds)
+	if err != nil {
+		return nil, err
+	}
+	menuById := map[uint]domain.Menu{}
+	for _, menu := range plannedMenus {
+		menuById[menu.MenuId] = menu
+	}
+
+	// 既存の献立から、埋まっている時間帯・使用済みのメニュー・ジャンルの登場回数を集計
+	taken := map[string]bool{}
+	used := map[uint]bool{}
+	genreCounts := map[uint]int{}
+	for _, plan := range plans {
+		taken[plan.PlannedOn+" "+plan.MealSlot] = true
+		used[plan.MenuID] = true
+		for _, genreId := range menuById[plan.MenuID].GenreIds {
+			genreCounts[genreId]++
+		}
+	}
+
+	favorites := toIdSet(favoriteIds)
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+
+	entries := []domain.MealPlanEntry{}
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		for _, slot := range slots {
+			if taken[date.Format(domain.DateLayout)+" "+slot] {
+				continue
+			}
+
+			// その週にまだ使っていないメニューを優先し、なければ再利用する
+			pool := []domain.Menu{}
+			for _, menu := range candidates {
+				if !used[menu.MenuId] {
+					pool = append(pool, menu)
+				}
+			}
+			if len(pool) == 0 {
+				pool = candidates
+			}
+
+			weights := make([]float64, len(pool))
+			for i, menu := range pool {
+				weights[i] = 1
+				if favorites[menu.MenuId] {
+					weights[i] = autoFillFavoriteWeight
+				}
+				// ジャンルの偏りを避ける
+				appearances := 0
+				for _, genreId := range menu.GenreIds {
+					appearances += genreCounts[genreId]
+				}
+				weights[i] /= float64(1 + appearances)
+			}
+
+			menu := pickWeighted(pool, weights, 1, rng)[0]
+			entries = append(entries, domain.MealPlanEntry{
+				PlannedOn: date,
+				MealSlot:  slot,
+				MenuID:    menu.MenuId,
+			})
+			used[menu.MenuId] = true
+			for _, genreId := range menu.GenreIds {
+				genreCounts[genreId]++
+			}
+		}
+	}
+
+	if _, err := u.mealPlanPort.AddMealPlans(userId, entries); err != nil {
+		return nil, err
+	}
+
+	return u.mealPlanPort.GetMealPlans(userId, start, end)
+}
+
+// getOwnMealPlan は献立を取得し、本人のものであることを確認する
+func (u MealPlanUsecase) getOwnMealPlan(userId uint, planId uint) (domain.MealPlan, error) {
+	plan, err := u.mealPlanPort.GetMealPlan(planId)
+	if err != nil {
+		return domain.MealPlan{}, err
+	}
+
+	if plan.UserID != userId {
+		return domain.MealPlan{}, domain.NewForbidden("meal_plan_forbidden", "you can only change your own meal plan")
+	}
+
+	return plan, nil
+}
+
+// weekRange は指定日を含む週の月曜日と日曜日を返す
+func weekRange(day time.Time) (time.Time, time.Time) {
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	offset := (int(day.Weekday()) + 6) % 7
+	start := day.AddDate(0, 0, -offset)
+	return start, start.AddDate(0, 0, 6)
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"fmt"
+	"go-menu/domain"
+	"strings"
+)
+
+// 一度に一括登録できる最大行数
+const maxImportRows = 1000
+
+// ImportMenus はメニューをまとめて登録する
+// ジャンル・カテゴリは登録済みのものを名前で解決する
+// dryRunの場合は登録せずに行ごとの検証結果を返す
+// dryRunでない場合は1行でも問題があれば何も登録せずに検証エラーを返す
+func (u MenuUsecase) ImportMenus(rows []domain.MenuImportRow, dryRun bool) (domain.MenuImportResult, error) {
+	if len(rows) == 0 {
+		return domain.MenuImportResult{}, domain.NewValidation([]domain.FieldError{{Field: "rows", Message: "must not be empty"}})
+	}
+	if len(rows) > maxImportRows {
+		return domain.MenuImportResult{}, domain.NewValidation([]domain.FieldError{{Field: "rows", Message: fmt.Sprintf("must have at most %d rows", maxImportRows)}})
+	}
+
+	genres, err := u.genrePort.GetAll()
+	if err != nil {
+		return domain.MenuImportResult{}, err
+	}
+	genreIdByName := map[string]uint{}
+	for _, genre := range genres {
+		genreIdByName[genre.GenreName] = genre.GenreId
+	}
+
+	categories, err := u.categoryPort.GetAll()
+	if err != nil {
+		return domain.MenuImportResult{}, err
+	}
+	categoryIdByName := map[string]uint{}
+	for _, category := range categories {
+		categoryIdByName[category.CategoryName] = category.CategoryId
+	}
+
+	menus := []domain.Menu{}
+	rowErrors := []domain.FieldError{}
+	for _, row := range rows {
+		menu := domain.Menu{
+			MenuName:       strings.TrimSpace(row.MenuName),
+			Description:    row.Description,
+			Price:          row.Price,
+			Calories:       row.Calories,
+			CookingMinutes: row.CookingMinutes,
+			ImageUrl:       strings.TrimSpace(row.ImageUrl),
+		}
+
+		var fields []domain.FieldError
+		fields = append(fields, row.Errors...)
+		fields = append(fields, validateMenuName(menu.MenuName)...)
+		fields = append(fields, validateMenuAttributes(menu)...)
+
+		var unknown []string
+		menu.GenreIds, unknown = resolveNames(row.GenreNames, genreIdByName)
+		if len(unknown) > 0 {
+			fields = append(fields, domain.FieldError{Field: "genres", Message: "unknown genres: " + strings.Join(unknown, ", ")})
+		}
+		menu.CategoryIds, unknown = resolveNames(row.CategoryNames, categoryIdByName)
+		if len(unknown) > 0 {
+			fields = append(fields, domain.FieldError{Field: "categories", Message: "unknown categories: " + strings.Join(unknown, ", ")})
+		}
+
+		for _, field := range fields {
+			field.Row = row.Row
+			rowErrors = append(rowErrors, field)
+		}
+		menus = append(menus, menu)
+	}
+
+	result := domain.MenuImportResult{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Errors: rowErrors,
+	}
+
+	if dryRun {
+		return result, nil
+	}
+	if len(rowErrors) > 0 {
+		return domain.MenuImportResult{}, domain.NewValidation(rowErrors)
+	}
+
+	result.Menus, err = u.menuPort.CreateMenus(menus)
+	if err != nil {
+		return domain.MenuImportResult{}, err
+	}
+
+	return result, nil
+}
+
+// resolveNames は名前をIDに変換する（重複した名前は1つにまとめる）
+// 登録されていない名前は2つ目の戻り値で返す
+func resolveNames(names []string, idByName map[string]uint) ([]uint, []string) {
+	ids := []uint{}
+	var unknown []string
+	seen := map[uint]bool{}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		id, ok := idByName[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, unknown
+}
@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"math/rand/v2"
+	"sort"
+)
+
+// ランダムに選ぶ候補の最大件数（メニューが多い場合はシードで決まる順に絞り込む）
+const maxRandomCandidates = 500
+
+// PickRandomMenus は条件に一致するメニューからランダムに重複なしで選ぶ
+// 同じシードと条件であれば同じ結果を返す
+func (u MenuUsecase) PickRandomMenus(query domain.RandomMenuQuery) ([]domain.Menu, error) {
+	// ログインユーザーの食事制限プロファイルに反するメニューを除外する
+	if query.UserId != 0 {
+		profile, err := u.profilePort.GetDietaryProfile(query.UserId)
+		if err != nil {
+			return nil, err
+		}
+		query.ExcludeAllergens = append(query.ExcludeAllergens, profile.Allergens...)
+		query.RequireDiets = append(query.RequireDiets, profile.Diets...)
+	}
+
+	// 重み付けするお気に入りは候補の上限に関わらず含める
+	var favoriteIds []uint
+	if query.UserId != 0 && query.FavoriteWeight > 1 {
+		var err error
+		favoriteIds, err = u.favoritePort.GetFavoriteMenuIds(query.UserId)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	candidates, err := u.menuPort.FindCandidates(domain.MenuCandidateQuery{
+		GenreIds:         query.GenreIds,
+		CategoryIds:      query.CategoryIds,
+		ExcludeIds:       query.ExcludeIds,
+		ExcludeAllergens: query.ExcludeAllergens,
+		RequireDiets:     query.RequireDiets,
+		PreferIds:        favoriteIds,
+		Limit:            maxRandomCandidates,
+		Seed:             query.Seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortMenusById(candidates)
+
+	// 重み付け（お気に入りは選ばれやすくする）
+	favorites := toIdSet(favoriteIds)
+	weights := make([]float64, len(candidates))
+	for i, candidate := range candidates {
+		weights[i] = 1
+		if favorites[candidate.MenuId] {
+			weights[i] = query.FavoriteWeight
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(query.Seed), 0))
+	return pickWeighted(candidates, weights, query.Count, rng), nil
+}
+
+// sortMenusById は取得順に依存せず同じシードで同じ結果になるようmenu_id順に並べ替える
+func sortMenusById(menus []domain.Menu) {
+	sort.Slice(menus, func(i, j int) bool {
+		return menus[i].MenuId < menus[j].MenuId
+	})
+}
+
+// pickWeighted は重みに比例した確率で重複なしにcount件を選ぶ
+func pickWeighted(candidates []domain.Menu, weights []float64, count int, rng *rand.Rand) []domain.Menu {
+	candidates = append([]domain.Menu{}, candidates...)
+	weights = append([]float64{}, weights...)
+
+	picked := []domain.Menu{}
+	for len(picked) < count && len(candidates) > 0 {
+		var total float64
+		for _, weight := range weights {
+			total += weight
+		}
+
+		// 累積の重みから選ぶ
+		target := rng.Float64() * total
+		index := len(candidates) - 1
+		for i, weight := range weights {
+			if target < weight {
+				index = i
+				break
+			}
+			target -= weight
+		}
+
+		picked = append(picked, candidates[index])
+		candidates = append(candidates[:index], candidates[index+1:]...)
+		weights = append(weights[:index], weights[index+1:]...)
+	}
+
+	return picked
+}
+
+// toIdSet はIDのリストを集合に変換する
+func toIdSet(ids []uint) map[uint]bool {
+	set := map[uint]bool{}
+	for _, id := range ids {
+		set[id] = true
+	}
+
+	return set
+}
+
+// containsAny はIDのいずれかが集合に含まれるかを判定する
+func containsAny(set map[uint]bool, ids []uint) bool {
+	for _, id := range ids {
+		if set[id] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+	"reflect"
+	"testing"
+)
+
+// stubMenuPort はFindCandidatesの条件をSQLの代わりにメモリ上で適用するMenuPort
+type stubMenuPort struct {
+	port.MenuPort
+	menus []domain.Menu
+	// 最後に受け取った候補の条件
+	query *domain.MenuCandidateQuery
+}
+
+func (p stubMenuPort) FindCandidates(query domain.MenuCandidateQuery) ([]domain.Menu, error) {
+	if p.query != nil {
+		*p.query = query
+	}
+
+	excludes := toIdSet(query.ExcludeIds)
+	genres := toIdSet(query.GenreIds)
+	categories := toIdSet(query.CategoryIds)
+	menus := []domain.Menu{}
+	for _, menu := range p.menus {
+		if excludes[menu.MenuId] {
+			continue
+		}
+		if len(genres) > 0 && !containsAny(genres, menu.GenreIds) {
+			continue
+		}
+		if len(categories) > 0 && !containsAny(categories, menu.CategoryIds) {
+			continue
+		}
+		if !satisfiesDietaryRestrictions(menu, query.ExcludeAllergens, query.RequireDiets) {
+			continue
+		}
+		menus = append(menus, menu)
+	}
+
+	return menus, nil
+}
+
+// stubFavoritePort はGetFavoriteMenuIdsだけを固定のIDで返すFavoritePort
+type stubFavoritePort struct {
+	port.FavoritePort
+	menuIds []uint
+}
+
+func (p stubFavoritePort) GetFavoriteMenuIds(userId uint) ([]uint, error) {
+	return p.menuIds, nil
+}
+
+// stubProfilePort は固定の食事制限プロファイルを返すDietaryProfilePort
+type stubProfilePort struct {
+	port.DietaryProfilePort
+	profile domain.DietaryProfile
+}
+
+func (p stubProfilePort) GetDietaryProfile(userId uint) (domain.DietaryProfile, error) {
+	return p.profile, nil
+}
+
+// pickerMenus はmenu_id 1〜10のメニューを返す
+// 奇数はジャンル1とカテゴリ1、偶数はジャンル2とカテゴリ2を持つ
+func pickerMenus() []domain.Menu {
+	menus := []domain.Menu{}
+	// 取得順に依存しないことを確かめるため逆順に並べる
+	for id := uint(10); id >= 1; id-- {
+		group := 2 - id%2
+		menu := domain.Menu{
+			MenuId:      id,
+			GenreIds:    []uint{group},
+			CategoryIds: []uint{group},
+		}
+		// 3の倍数は卵を含む
+		if id%3 == 0 {
+			menu.Allergens = []string{"egg"}
+		}
+		menus = append(menus, menu)
+	}
+
+	return menus
+}
+
+func newPickerUsecase(favoriteIds []uint) MenuUsecase {
+	return ProvideMenuUsecase(
+		stubMenuPort{menus: pickerMenus()},
+		nil,
+		nil,
+		stubFavoritePort{menuIds: favoriteIds},
+		stubProfilePort{},
+	)
+}
+
+func menuIds(menus []domain.Menu) []uint {
+	ids := []uint{}
+	for _, menu := range menus {
+		ids = append(ids, menu.MenuId)
+	}
+
+	return ids
+}
+
+func TestPickRandomMenus(t *testing.T) {
+	tests := []struct {
+		name  string
+		query domain.RandomMenuQuery
+		// 結果に含まれてよいmenu_id
+		allowed map[uint]bool
+		// 期待する件数
+		want int
+	}{
+		{
+			name:  "候補数以下の件数",
+			query: domain.RandomMenuQuery{Count: 5, Seed: 1},
+			want:  5,
+		},
+		{
+			name:  "候補数を超える件数は候補数まで",
+			query: domain.RandomMenuQuery{Count: 20, Seed: 2},
+			want:  10,
+		},
+		{
+			name:    "除外したメニューは選ばれない",
+			query:   domain.RandomMenuQuery{Count: 10, Seed: 3, ExcludeIds: []uint{1, 2, 3}},
+			allowed: map[uint]bool{4: true, 5: true, 6: true, 7: true, 8: true, 9: true, 10: true},
+			want:    7,
+		},
+		{
+			name:    "ジャンルで絞り込む",
+			query:   domain.RandomMenuQuery{Count: 10, Seed: 4, GenreIds: []uint{1}},
+			allowed: map[uint]bool{1: true, 3: true, 5: true, 7: true, 9: true},
+			want:    5,
+		},
+		{
+			name:    "カテゴリで絞り込む",
+			query:   domain.RandomMenuQuery{Count: 3, Seed: 5, CategoryIds: []uint{2}},
+			allowed: map[uint]bool{2: true, 4: true, 6: true, 8: true, 10: true},
+			want:    3,
+		},
+		{
+			name:    "除外と絞り込みを組み合わせる",
+			query:   domain.RandomMenuQuery{Count: 10, Seed: 6, GenreIds: []uint{2}, ExcludeIds: []uint{4, 8}},
+			allowed: map[uint]bool{2: true, 6: true, 10: true},
+			want:    3,
+		},
+		{
+			name:  "一致する候補がない",
+			query: domain.RandomMenuQuery{Count: 3, Seed: 7, GenreIds: []uint{99}},
+			want:  0,
+		},
+		{
+			name:  "お気に入りの重み付けあり",
+			query: domain.RandomMenuQuery{Count: 10, Seed: 8, UserId: 1, FavoriteWeight: 5},
+			want:  10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := newPickerUsecase([]uint{1})
+
+			first, err := u.PickRandomMenus(tt.query)
+			if err != nil {
+				t.Fatalf("PickRandomMenus() error = %v", err)
+			}
+			second, err := u.PickRandomMenus(tt.query)
+			if err != nil {
+				t.Fatalf("PickRandomMenus() error = %v", err)
+			}
+
+			// 同じシードでは同じ結果になる
+			if !reflect.DeepEqual(menuIds(first), menuIds(second)) {
+				t.Errorf("same seed returned %v and %v", menuIds(first), menuIds(second))
+			}
+
+			if len(first) != tt.want {
+				t.Errorf("got %d menus, want %d", len(first), tt.want)
+			}
+
+			seen := map[uint]bool{}
+			for _, id := range menuIds(first) {
+				if seen[id] {
+					t.Errorf("menu %d picked twice in %v", id, menuIds(first))
+				}
+				seen[id] = true
+				if tt.allowed != nil && !tt.allowed[id] {
+					t.Errorf("menu %d should not be picked", id)
+				}
+			}
+		})
+	}
+}
+
+func TestPickRandomMenusDifferentSeeds(t *testing.T) {
+	u := newPickerUsecase(nil)
+
+	// シードが異なれば少なくとも一部の結果は変わる
+	base, err := u.PickRandomMenus(domain.RandomMenuQuery{Count: 5, Seed: 1})
+	if err != nil {
+		t.Fatalf("PickRandomMenus() error = %v", err)
+	}
+	for seed := int64(2); seed <= 20; seed++ {
+		menus, err := u.PickRandomMenus(domain.RandomMenuQuery{Count: 5, Seed: seed})
+		if err != nil {
+			t.Fatalf("PickRandomMenus() error = %v", err)
+		}
+		if !reflect.DeepEqual(menuIds(base), menuIds(menus)) {
+			return
+		}
+	}
+	t.Errorf("seeds 1-20 all returned %v", menuIds(base))
+}
+
+func TestPickRandomMenusFavoriteWeight(t *testing.T) {
+	const trials = 2000
+	favoriteId := uint(7)
+
+	// お気に入りが1件だけ選ばれた回数を数える
+	countFavorite := func(userId uint, weight float64) int {
+		u := newPickerUsecase([]uint{favoriteId})
+		count := 0
+		for seed := int64(0); seed < trials; seed++ {
+			menus, err := u.PickRandomMenus(domain.RandomMenuQuery{
+				Count:          1,
+				Seed:           seed,
+				UserId:         userId,
+				FavoriteWeight: weight,
+			})
+			if err != nil {
+				t.Fatalf("PickRandomMenus() error = %v", err)
+			}
+			if len(menus) == 1 && menus[0].MenuId == favoriteId {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	// 重み付けなしは10件から均等（約10%）
+	unweighted := countFavorite(0, 0)
+	// 重み10では10/19（約53%）
+	weighted := countFavorite(1, 10)
+
+	if unweighted > trials/5 {
+		t.Errorf("unweighted favorite picked %d/%d times, want about 10%%", unweighted, trials)
+	}
+	if weighted < trials*2/5 {
+		t.Errorf("weighted favorite picked %d/%d times, want about 53%%", weighted, trials)
+	}
+	if weighted <= unweighted*3 {
+		t.Errorf("favorite weight had little effect: weighted %d, unweighted %d", weighted, unweighted)
+	}
+}
+
+func TestPickRandomMenusCandidateQuery(t *testing.T) {
+	var query domain.MenuCandidateQuery
+	u := ProvideMenuUsecase(
+		stubMenuPort{menus: pickerMenus(), query: &query},
+		nil,
+		nil,
+		stubFavoritePort{menuIds: []uint{2, 4}},
+		stubProfilePort{profile: domain.DietaryProfile{Allergens: []string{"egg"}}},
+	)
+
+	menus, err := u.PickRandomMenus(domain.RandomMenuQuery{
+		Count:          10,
+		Seed:           9,
+		GenreIds:       []uint{1, 2},
+		ExcludeIds:     []uint{1},
+		UserId:         1,
+		FavoriteWeight: 3,
+	})
+	if err != nil {
+		t.Fatalf("PickRandomMenus() error = %v", err)
+	}
+
+	// 絞り込みは候補の取得条件として渡し、メモリ上では絞り込まない
+	want := domain.MenuCandidateQuery{
+		GenreIds:         []uint{1, 2},
+		ExcludeIds:       []uint{1},
+		ExcludeAllergens: []string{"egg"},
+		PreferIds:        []uint{2, 4},
+		Limit:            maxRandomCandidates,
+		Seed:             9,
+	}
+	if !reflect.DeepEqual(query, want) {
+		t.Errorf("candidate query = %+v, want %+v", query, want)
+	}
+
+	// 食事制限プロファイルに反するメニュー（3, 6, 9）と除外したメニューは選ばれない
+	for _, id := range menuIds(menus) {
+		if id == 1 || id%3 == 0 {
+			t.Errorf("menu %d should not be picked", id)
+		}
+	}
+	if len(menus) != 6 {
+		t.Errorf("got %d menus, want 6", len(menus))
+	}
+}
+
+// satisfiesDietaryRestrictions はSQLの代わりに、メニューが除外するアレルゲンを含まず、必要な食事制限すべてに対応しているかを判定する
+func satisfiesDietaryRestrictions(menu domain.Menu, excludeAllergens []string, requireDiets []string) bool {
+	allergens := map[string]bool{}
+	for _, allergen := range menu.Allergens {
+		allergens[allergen] = true
+	}
+	for _, allergen := range excludeAllergens {
+		if allergens[allergen] {
+			return false
+		}
+	}
+
+	diets := map[string]bool{}
+	for _, diet := range menu.Diets {
+		diets[diet] = true
+	}
+	for _, diet := range requireDiets {
+		if !diets[diet] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// メニュー名の最大文字数（menu_list.menu_nameのサイズに合わせる）
+	maxMenuNameLength = 50
+	// 説明の最大文字数
+	maxDescriptionLength = 2000
+	// 画像URLの最大文字数（menu_list.image_urlのサイズに合わせる）
+	maxImageUrlLength = 2048
+)
+
+// validateMenu はメニューの作成・更新内容を検証する
+func (u MenuUsecase) validateMenu(menu domain.Menu) error {
+	var fields []domain.FieldError
+
+	fields = append(fields, validateMenuName(menu.MenuName)...)
+	fields = append(fields, validateMenuAttributes(menu)...)
+
+	genreFields, err := u.validateGenreIds(menu.GenreIds)
+	if err != nil {
+		return err
+	}
+	fields = append(fields, genreFields...)
+
+	categoryFields, err := u.validateCategoryIds(menu.CategoryIds)
+	if err != nil {
+		return err
+	}
+	fields = append(fields, categoryFields...)
+
+	return toValidationError(fields)
+}
+
+// validateMenuName はメニュー名を検証する
+func validateMenuName(menuName string) []domain.FieldError {
+	if strings.TrimSpace(menuName) == "" {
+		return []domain.FieldError{{Field: "menu_name", Message: "must not be empty"}}
+	}
+	if utf8.RuneCountInString(menuName) > maxMenuNameLength {
+		return []domain.FieldError{{Field: "menu_name", Message: "must be at most 50 characters"}}
+	}
+
+	return nil
+}
+
+// validateMenuAttributes はメニューの任意項目を検証する
+func validateMenuAttributes(menu domain.Menu) []domain.FieldError {
+	var fields []domain.FieldError
+
+	if utf8.RuneCountInString(menu.Description) > maxDescriptionLength {
+		fields = append(fields, domain.FieldError{Field: "description", Message: "must be at most 2000 characters"})
+	}
+
+	if menu.ImageUrl != "" {
+		u, err := url.Parse(menu.ImageUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fields = append(fields, domain.FieldError{Field: "image_url", Message: "must be an absolute http(s) URL"})
+		} else if len(menu.ImageUrl) > maxImageUrlLength {
+			fields = append(fields, domain.FieldError{Field: "image_url", Message: "must be at most 2048 characters"})
+		}
+	}
+
+	return fields
+}
+
+// validateGenreIds はジャンルIDの重複と存在を検証する
+func (u MenuUsecase) validateGenreIds(genreIds []uint) ([]domain.FieldError, error) {
+	if duplicates := findDuplicateIds(genreIds); len(duplicates) > 0 {
+		return []domain.FieldError{{Field: "genre_ids", Message: "contains duplicate ids", Values: duplicates}}, nil
+	}
+
+	genres, err := u.genrePort.GetByIds(genreIds)
+	if err != nil {
+		return nil, err
+	}
+
+	existingIds := make([]uint, 0, len(genres))
+	for _, genre := range genres {
+		existingIds = append(existingIds, genre.GenreId)
+	}
+	if missing := findMissingIds(genreIds, existingIds); len(missing) > 0 {
+		return []domain.FieldError{{Field: "genre_ids", Message: "contains unknown ids", Values: missing}}, nil
+	}
+
+	return nil, nil
+}
+
+// validateCategoryIds はカテゴリIDの重複と存在を検証する
+func (u MenuUsecase) validateCategoryIds(categoryIds []uint) ([]domain.FieldError, error) {
+	if duplicates := findDuplicateIds(categoryIds); len(duplicates) > 0 {
+		return []domain.FieldError{{Field: "category_ids", Message: "contains duplicate ids", Values: duplicates}}, nil
+	}
+
+	categories, err := u.categoryPort.GetByIds(categoryIds)
+	if err != nil {
+		return nil, err
+	}
+
+	existingIds := make([]uint, 0, len(categories))
+	for _, category := range categories {
+		existingIds = append(existingIds, category.CategoryId)
+	}
+	if missing := findMissingIds(categoryIds, existingIds); len(missing) > 0 {
+		return []domain.FieldError{{Field: "category_ids", Message: "contains unknown ids", Values: missing}}, nil
+	}
+
+	return nil, nil
+}
+
+// validateTags はアレルゲンや食事制限など、決められた値のリストを検証する
+func validateTags(field string, values []string, allowed []string) []domain.FieldError {
+	var fields []domain.FieldError
+
+	if duplicates := findDuplicates(values); len(duplicates) > 0 {
+		fields = append(fields, domain.FieldError{Field: field, Message: "contains duplicate values: " + strings.Join(duplicates, ", ")})
+	}
+
+	allowedSet := map[string]bool{}
+	for _, value := range allowed {
+		allowedSet[value] = true
+	}
+	var unknown []string
+	for _, value := range values {
+		if !allowedSet[value] {
+			unknown = append(unknown, value)
+		}
+	}
+	if len(unknown) > 0 {
+		fields = append(fields, domain.FieldError{Field: field, Message: "contains unknown values: " + strings.Join(unknown, ", ") + " (allowed: " + strings.Join(allowed, ", ") + ")"})
+	}
+
+	return fields
+}
+
+// findDuplicateIds は重複して指定されたIDを返す
+func findDuplicateIds(ids []uint) []uint {
+	return findDuplicates(ids)
+}
+
+// findDuplicates は重複して指定された値を返す
+func findDuplicates[T comparable](ids []T) []T {
+	var duplicates []T
+	counts := map[T]int{}
+
+	for _, id := range ids {
+		counts[id]++
+		if counts[id] == 2 {
+			duplicates = append(duplicates, id)
+		}
+	}
+
+	return duplicates
+}
+
+// findMissingIds は指定されたIDのうち存在しないものを返す
+func findMissingIds(ids []uint, existingIds []uint) []uint {
+	var missing []uint
+	exists := map[uint]bool{}
+
+	for _, id := range existingIds {
+		exists[id] = true
+	}
+	for _, id := range ids {
+		if !exists[id] {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing
+}
+
+// toValidationError は検証エラーがあればValidationErrorにまとめる
+func toValidationError(fields []domain.FieldError) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return domain.NewValidation(fields)
+}
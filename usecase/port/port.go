@@ -1,12 +1,75 @@
 package port
 
-import "go-menu/domain"
+import (
+	"go-menu/domain"
+	"time"
+)
 
 type MenuPort interface {
 	GetAll() ([]domain.Menu, error)
+	EachMenuBatch(fn func(menus []domain.Menu) error) error
+	FindMenus(query domain.MenuQuery) (domain.MenuPage, error)
+	FindCandidates(query domain.MenuCandidateQuery) ([]domain.Menu, error)
+	GetMenu(menuId uint) (domain.Menu, error)
+	GetMenusByIds(menuIds []uint) ([]domain.Menu, error)
 	CreateMenu(menu domain.Menu) (domain.Menu, error)
+	CreateMenus(menus []domain.Menu) ([]domain.Menu, error)
 	UpdateMenu(menu domain.Menu) (domain.Menu, error)
 	UpdateGenreRelations(menuId uint, genreIds []uint) (domain.Menu, error)
 	UpdateCategoryRelations(menuId uint, categoryIds []uint) (domain.Menu, error)
+	UpdateAllergens(menuId uint, allergens []string) (domain.Menu, error)
+	UpdateDiets(menuId uint, diets []string) (domain.Menu, error)
+	GetRecipe(menuId uint) (domain.Recipe, error)
+	GetIngredients(menuIds []uint) ([]domain.Recipe, error)
+	UpdateRecipe(recipe domain.Recipe) (domain.Recipe, error)
 	DeleteMenu(menuId uint) error
+	RestoreMenu(menuId uint) (domain.Menu, error)
+	PurgeDeletedMenus(deletedBefore time.Time) (int64, error)
+}
+
+type GenrePort interface {
+	GetAll() ([]domain.Genre, error)
+	GetByIds(genreIds []uint) ([]domain.Genre, error)
+	CreateGenre(genre domain.Genre) (domain.Genre, error)
+	UpdateGenre(genre domain.Genre) (domain.Genre, error)
+	DeleteGenre(genreId uint, cascade bool) error
+}
+
+type CategoryPort interface {
+	GetAll() ([]domain.Category, error)
+	GetByIds(categoryIds []uint) ([]domain.Category, error)
+	CreateCategory(category domain.Category) (domain.Category, error)
+	UpdateCategory(category domain.Category) (domain.Category, error)
+	DeleteCategory(categoryId uint, cascade bool) error
+}
+
+type FavoritePort interface {
+	GetFavoriteMenuIds(userId uint) ([]uint, error)
+	FindFavorites(query domain.FavoriteQuery) (domain.FavoritePage, error)
+}
+
+type SuggestionPort interface {
+	RecordSuggestions(userId uint, menuIds []uint, suggestedAt time.Time) ([]domain.Suggestion, error)
+	GetSuggestion(suggestionId uint) (domain.Suggestion, error)
+	AcceptSuggestion(suggestionId uint, acceptedAt time.Time) (domain.Suggestion, error)
+	GetAcceptedSuggestions(userId uint, since time.Time) ([]domain.Suggestion, error)
+}
+
+type MealPlanPort interface {
+	AddMealPlans(userId uint, entries []domain.MealPlanEntry) ([]domain.MealPlan, error)
+	GetMealPlans(userId uint, from, to time.Time) ([]domain.MealPlan, error)
+	GetMealPlan(planId uint) (domain.MealPlan, error)
+	MoveMealPlan(planId uint, plannedOn time.Time, mealSlot string) (domain.MealPlan, error)
+	RemoveMealPlan(planId uint) error
+}
+
+type CalendarTokenPort interface {
+	SaveCalendarToken(userId uint, tokenHash string) error
+	DeleteCalendarToken(userId uint) error
+	FindUserIdByCalendarToken(tokenHash string) (uint, error)
+}
+
+type DietaryProfilePort interface {
+	GetDietaryProfile(userId uint) (domain.DietaryProfile, error)
+	UpdateDietaryProfile(userId uint, profile domain.DietaryProfile) (domain.DietaryProfile, error)
 }
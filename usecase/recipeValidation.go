@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"fmt"
+	"go-menu/domain"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// 材料名の最大文字数（menu_ingredients.nameのサイズに合わせる）
+	maxIngredientNameLength = 100
+	// 単位の最大文字数（menu_ingredients.unitのサイズに合わせる）
+	maxUnitLength = 32
+	// 調理手順の最大文字数
+	maxInstructionLength = 1000
+	// 材料と調理手順の最大件数
+	maxRecipeItems = 100
+)
+
+// validateRecipe はレシピの更新内容を検証する
+func validateRecipe(recipe domain.Recipe) error {
+	var fields []domain.FieldError
+
+	if len(recipe.Ingredients) > maxRecipeItems {
+		fields = append(fields, domain.FieldError{Field: "ingredients", Message: fmt.Sprintf("must have at most %d items", maxRecipeItems)})
+	}
+	for i, ingredient := range recipe.Ingredients {
+		field := fmt.Sprintf("ingredients[%d]", i)
+		if strings.TrimSpace(ingredient.Name) == "" {
+			fields = append(fields, domain.FieldError{Field: field + ".name", Message: "must not be empty"})
+		} else if utf8.RuneCountInString(ingredient.Name) > maxIngredientNameLength {
+			fields = append(fields, domain.FieldError{Field: field + ".name", Message: fmt.Sprintf("must be at most %d characters", maxIngredientNameLength)})
+		}
+		if ingredient.Quantity < 0 {
+			fields = append(fields, domain.FieldError{Field: field + ".quantity", Message: "must not be negative"})
+		}
+		if utf8.RuneCountInString(ingredient.Unit) > maxUnitLength {
+			fields = append(fields, domain.FieldError{Field: field + ".unit", Message: fmt.Sprintf("must be at most %d characters", maxUnitLength)})
+		}
+		if !slices.Contains(domain.StoreSections, ingredient.Section) {
+			fields = append(fields, domain.FieldError{Field: field + ".section", Message: "must be one of " + strings.Join(domain.StoreSections, ", ")})
+		}
+	}
+
+	if len(recipe.Steps) > maxRecipeItems {
+		fields = append(fields, domain.FieldError{Field: "steps", Message: fmt.Sprintf("must have at most %d items", maxRecipeItems)})
+	}
+	for i, step := range recipe.Steps {
+		field := fmt.Sprintf("steps[%d].instruction", i)
+		if strings.TrimSpace(step.Instruction) == "" {
+			fields = append(fields, domain.FieldError{Field: field, Message: "must not be empty"})
+		} else if utf8.RuneCountInString(step.Instruction) > maxInstructionLength {
+			fields = append(fields, domain.FieldError{Field: field, Message: fmt.Sprintf("must be at most %d characters", maxInstructionLength)})
+		}
+	}
+
+	return toValidationError(fields)
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"fmt"
+	"go-menu/domain"
+	"go-menu/usecase/port"
+	"math"
+	"strings"
+)
+
+const (
+	// 買い物リストに指定できるメニューの最大数
+	maxShoppingListItems = 50
+	// 1メニューあたりの最大人数
+	maxServings = 100
+)
+
+type ShoppingListUsecase struct {
+	menuPort port.MenuPort
+}
+
+func ProvideShoppingListUsecase(menuPort port.MenuPort) ShoppingListUsecase {
+	return ShoppingListUsecase{menuPort}
+}
+
+// shoppingListKey は分量を合算する単位（材料名と単位が同じものを合算する）
+type shoppingListKey struct {
+	name string
+	unit string
+}
+
+// CreateShoppingList は指定されたメニューと人数から買い物リストを作成する
+// 同じ名前・単位の材料は分量を合算し、売り場ごとにまとめる
+func (u ShoppingListUsecase) CreateShoppingList(items []domain.ShoppingListItem) (domain.ShoppingList, error) {
+	if err := validateShoppingListItems(items); err != nil {
+		return domain.ShoppingList{}, err
+	}
+
+	// メニューごとの材料をまとめて取得
+	menuIds := []uint{}
+	for _, item := range items {
+		menuIds = append(menuIds, item.MenuId)
+	}
+	results, err := u.menuPort.GetIngredients(menuIds)
+	if err != nil {
+		return domain.ShoppingList{}, err
+	}
+	recipeOf := map[uint]domain.Recipe{}
+	for _, recipe := range results {
+		recipeOf[recipe.MenuId] = recipe
+	}
+
+	recipes := []domain.Recipe{}
+	var missing []uint
+	for _, item := range items {
+		recipe, ok := recipeOf[item.MenuId]
+		if !ok {
+			missing = append(missing, item.MenuId)
+			continue
+		}
+		recipes = append(recipes, recipe)
+	}
+	if len(missing) > 0 {
+		return domain.ShoppingList{}, domain.NewValidation([]domain.FieldError{{Field: "items", Message: "contains unknown menu ids", Values: missing}})
+	}
+
+	// 材料を合算（最初に現れた順を保つ）
+	sectionOf := map[shoppingListKey]string{}
+	quantities := map[shoppingListKey]float64{}
+	var keys []shoppingListKey
+	for i, recipe := range recipes {
+		for _, ingredient := range recipe.Ingredients {
+			key := shoppingListKey{name: strings.TrimSpace(ingredient.Name), unit: strings.TrimSpace(ingredient.Unit)}
+			if _, ok := quantities[key]; !ok {
+				keys = append(keys, key)
+				sectionOf[key] = ingredient.Section
+			}
+			quantities[key] += ingredient.Quantity * float64(items[i].Servings)
+		}
+	}
+
+	// 売り場ごとにまとめる
+	entries := map[string][]domain.ShoppingListEntry{}
+	for _, key := range keys {
+		section := sectionOf[key]
+		if section == "" {
+			section = domain.SectionOther
+		}
+		entries[section] = append(entries[section], domain.ShoppingListEntry{
+			Name:     key.name,
+			Quantity: roundQuantity(quantities[key]),
+			Unit:     key.unit,
+		})
+	}
+
+	list := domain.ShoppingList{Sections: []domain.ShoppingListSection{}}
+	for _, section := range domain.StoreSections {
+		if len(entries[section]) == 0 {
+			continue
+		}
+		list.Sections = append(list.Sections, domain.ShoppingListSection{
+			Section: section,
+			Items:   entries[section],
+		})
+	}
+
+	return list, nil
+}
+
+// validateShoppingListItems は買い物リストの対象メニューと人数を検証する
+func validateShoppingListItems(items []domain.ShoppingListItem) error {
+	var fields []domain.FieldError
+
+	if len(items) == 0 {
+		fields = append(fields, domain.FieldError{Field: "items", Message: "must not be empty"})
+	} else if len(items) > maxShoppingListItems {
+		fields = append(fields, domain.FieldError{Field: "items", Message: fmt.Sprintf("must have at most %d items", maxShoppingListItems)})
+	}
+
+	menuIds := []uint{}
+	for i, item := range items {
+		menuIds = append(menuIds, item.MenuId)
+		if item.Servings < 1 || item.Servings > maxServings {
+			fields = append(fields, domain.FieldError{Field: fmt.Sprintf("items[%d].servings", i), Message: fmt.Sprintf("must be between 1 and %d", maxServings)})
+		}
+	}
+	if duplicates := findDuplicateIds(menuIds); len(duplicates) > 0 {
+		fields = append(fields, domain.FieldError{Field: "items", Message: "contains duplicate menu ids", Values: duplicates})
+	}
+
+	return toValidationError(fields)
+}
+
+// roundQuantity は浮動小数点の誤差が表示されないよう分量を小数第2位で丸める
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*100) / 100
+}
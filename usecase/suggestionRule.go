@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"time"
+)
+
+// 「同じジャンルを続けない」ルールで直前の採用として扱う期間
+const consecutiveLookback = 30 * 24 * time.Hour
+
+// SuggestionContext はルールの判定に使うユーザーの状況
+type SuggestionContext struct {
+	Now time.Time
+	// 判定に必要な期間内に採用したメニュー（新しい順）
+	Accepted []domain.Menu
+	// 採用日時（Acceptedと同じ順）
+	AcceptedAt []time.Time
+	// 直前のメニューのジャンル（同じ提案内で選んだメニューも含む）
+	PreviousGenreIds []uint
+}
+
+// SuggestionRule はメニューを提案してよいかを判定するルール
+type SuggestionRule interface {
+	// Allow はメニューを提案してよい場合にtrueを返す
+	Allow(menu domain.Menu, ctx SuggestionContext) bool
+	// Lookback は判定に必要な採用履歴の期間を返す
+	Lookback() time.Duration
+}
+
+// SuggestionRules は複数のルールをすべて満たす場合のみ提案を許可する
+type SuggestionRules []SuggestionRule
+
+func (r SuggestionRules) Allow(menu domain.Menu, ctx SuggestionContext) bool {
+	for _, rule := range r {
+		if !rule.Allow(menu, ctx) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r SuggestionRules) Lookback() time.Duration {
+	var lookback time.Duration
+	for _, rule := range r {
+		lookback = max(lookback, rule.Lookback())
+	}
+
+	return lookback
+}
+
+// NoRecentAcceptedRule は直近Days日以内に採用したメニューを提案しない
+type NoRecentAcceptedRule struct {
+	Days int
+}
+
+func (r NoRecentAcceptedRule) Allow(menu domain.Menu, ctx SuggestionContext) bool {
+	since := ctx.Now.Add(-r.Lookback())
+	for i, accepted := range ctx.Accepted {
+		if ctx.AcceptedAt[i].Before(since) {
+			break
+		}
+		if accepted.MenuId == menu.MenuId {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r NoRecentAcceptedRule) Lookback() time.Duration {
+	return time.Duration(r.Days) * 24 * time.Hour
+}
+
+// NoSameGenreInARowRule は直前のメニューと同じジャンルのメニューを続けて提案しない
+type NoSameGenreInARowRule struct{}
+
+func (r NoSameGenreInARowRule) Allow(menu domain.Menu, ctx SuggestionContext) bool {
+	return !containsAny(toIdSet(ctx.PreviousGenreIds), menu.GenreIds)
+}
+
+func (r NoSameGenreInARowRule) Lookback() time.Duration {
+	return consecutiveLookback
+}
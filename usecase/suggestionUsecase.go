@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+	"math/rand/v2"
+	"time"
+)
+
+type SuggestionUsecase struct {
+	menuPort       port.MenuPort
+	suggestionPort port.SuggestionPort
+	profilePort    port.DietaryProfilePort
+}
+
+func ProvideSuggestionUsecase(menuPort port.MenuPort, suggestionPort port.SuggestionPort, profilePort port.DietaryProfilePort) SuggestionUsecase {
+	return SuggestionUsecase{menuPort, suggestionPort, profilePort}
+}
+
+// Suggest はルールを満たすメニューをランダムにcount件提案し、提案したことを記録する
+// 食事制限プロファイルに反するメニューは提案しない
+func (u SuggestionUsecase) Suggest(userId uint, rules SuggestionRules, count int, seed int64) ([]domain.Suggestion, error) {
+	profile, err := u.profilePort.GetDietaryProfile(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	menus, err := u.menuPort.FindCandidates(domain.MenuCandidateQuery{
+		ExcludeAllergens: profile.Allergens,
+		RequireDiets:     profile.Diets,
+		Limit:            maxRandomCandidates,
+		Seed:             seed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortMenusById(menus)
+
+	ctx, err := u.buildContext(userId, rules.Lookback(), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// 1件選ぶごとに直前のメニューを更新してルールを判定する
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+	picked := map[uint]bool{}
+	var pickedMenus []domain.Menu
+	for len(pickedMenus) < count {
+		candidates := []domain.Menu{}
+		for _, menu := range menus {
+			if !picked[menu.MenuId] && rules.Allow(menu, ctx) {
+				candidates = append(candidates, menu)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+
+		menu := candidates[rng.IntN(len(candidates))]
+		picked[menu.MenuId] = true
+		pickedMenus = append(pickedMenus, menu)
+		ctx.PreviousGenreIds = menu.GenreIds
+	}
+
+	menuIds := []uint{}
+	for _, menu := range pickedMenus {
+		menuIds = append(menuIds, menu.MenuId)
+	}
+
+	suggestions, err := u.suggestionPort.RecordSuggestions(userId, menuIds, ctx.Now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range suggestions {
+		suggestions[i].Menu = &pickedMenus[i]
+	}
+
+	return suggestions, nil
+}
+
+// Accept はユーザーが提案を採用したことを記録する
+func (u SuggestionUsecase) Accept(userId uint, suggestionId uint) (domain.Suggestion, error) {
+	suggestion, err := u.suggestionPort.GetSuggestion(suggestionId)
+	if err != nil {
+		return domain.Suggestion{}, err
+	}
+
+	// 自分への提案のみ採用可能
+	if suggestion.UserID != userId {
+		return domain.Suggestion{}, domain.NewForbidden("suggestion_forbidden", "you can only accept your own suggestions")
+	}
+
+	return u.suggestionPort.AcceptSuggestion(suggestionId, time.Now())
+}
+
+// buildContext は採用履歴からルールの判定に使う状況を作成する
+func (u SuggestionUsecase) buildContext(userId uint, lookback time.Duration, now time.Time) (SuggestionContext, error) {
+	ctx := SuggestionContext{Now: now}
+	if lookback <= 0 {
+		return ctx, nil
+	}
+
+	accepted, err := u.suggestionPort.GetAcceptedSuggestions(userId, now.Add(-lookback))
+	if err != nil {
+		return SuggestionContext{}, err
+	}
+
+	// 採用したメニューのジャンルを取得する
+	acceptedIds := []uint{}
+	for _, suggestion := range accepted {
+		acceptedIds = append(acceptedIds, suggestion.MenuID)
+	}
+	menus, err := u.menuPort.GetMenusByIds(acceptedIds)
+	if err != nil {
+		return SuggestionContext{}, err
+	}
+	menusById := map[uint]domain.Menu{}
+	for _, menu := range menus {
+		menusById[menu.MenuId] = menu
+	}
+
+	for _, suggestion := range accepted {
+		// 削除済みのメニューはジャンルが分からないためIDのみで扱う
+		menu, ok := menusById[suggestion.MenuID]
+		if !ok {
+			menu = domain.Menu{MenuId: suggestion.MenuID}
+		}
+		ctx.Accepted = append(ctx.Accepted, menu)
+		ctx.AcceptedAt = append(ctx.AcceptedAt, *suggestion.AcceptedAt)
+	}
+	if len(ctx.Accepted) > 0 {
+		ctx.PreviousGenreIds = ctx.Accepted[0].GenreIds
+	}
+
+	return ctx, nil
+}
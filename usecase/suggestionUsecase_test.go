@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"go-menu/domain"
+	"go-menu/usecase/port"
+	"testing"
+	"time"
+)
+
+// stubSuggestionPort は提案を記録するだけのSuggestionPort（採用履歴はない）
+type stubSuggestionPort struct {
+	port.SuggestionPort
+}
+
+func (p stubSuggestionPort) RecordSuggestions(userId uint, menuIds []uint, suggestedAt time.Time) ([]domain.Suggestion, error) {
+	suggestions := []domain.Suggestion{}
+	for i, menuId := range menuIds {
+		suggestions = append(suggestions, domain.Suggestion{SuggestionID: uint(i + 1), UserID: userId, MenuID: menuId, SuggestedAt: suggestedAt})
+	}
+
+	return suggestions, nil
+}
+
+func (p stubSuggestionPort) GetAcceptedSuggestions(userId uint, since time.Time) ([]domain.Suggestion, error) {
+	return nil, nil
+}
+
+func (p stubMenuPort) GetMenusByIds(menuIds []uint) ([]domain.Menu, error) {
+	ids := toIdSet(menuIds)
+	menus := []domain.Menu{}
+	for _, menu := range p.menus {
+		if ids[menu.MenuId] {
+			menus = append(menus, menu)
+		}
+	}
+
+	return menus, nil
+}
+
+func TestSuggestAppliesDietaryProfile(t *testing.T) {
+	var query domain.MenuCandidateQuery
+	u := ProvideSuggestionUsecase(
+		stubMenuPort{menus: pickerMenus(), query: &query},
+		stubSuggestionPort{},
+		stubProfilePort{profile: domain.DietaryProfile{Allergens: []string{"egg"}}},
+	)
+
+	suggestions, err := u.Suggest(1, SuggestionRules{NoRecentAcceptedRule{Days: 7}}, 10, 1)
+	if err != nil {
+		t.Fatalf("Suggest() error = %v", err)
+	}
+
+	if len(query.ExcludeAllergens) != 1 || query.ExcludeAllergens[0] != "egg" {
+		t.Errorf("candidate query ExcludeAllergens = %v, want [egg]", query.ExcludeAllergens)
+	}
+	// 卵を含むメニュー（3, 6, 9）は提案しない
+	if len(suggestions) != 7 {
+		t.Errorf("got %d suggestions, want 7", len(suggestions))
+	}
+	for _, suggestion := range suggestions {
+		if suggestion.MenuID%3 == 0 {
+			t.Errorf("menu %d contains an excluded allergen", suggestion.MenuID)
+		}
+	}
+}
@@ -3,14 +3,19 @@ package usecase
 import (
 	"go-menu/domain"
 	"go-menu/usecase/port"
+	"time"
 )
 
 type MenuUsecase struct {
-	menuPort port.MenuPort
+	menuPort     port.MenuPort
+	genrePort    port.GenrePort
+	categoryPort port.CategoryPort
+	favoritePort port.FavoritePort
+	profilePort  port.DietaryProfilePort
 }
 
-func ProvideMenuUsecase(menuPort port.MenuPort) MenuUsecase {
-	return MenuUsecase{menuPort}
+func ProvideMenuUsecase(menuPort port.MenuPort, genrePort port.GenrePort, categoryPort port.CategoryPort, favoritePort port.FavoritePort, profilePort port.DietaryProfilePort) MenuUsecase {
+	return MenuUsecase{menuPort, genrePort, categoryPort, favoritePort, profilePort}
 }
 
 func (u MenuUsecase) GetAll() ([]domain.Menu, error) {
@@ -23,7 +28,46 @@ func (u MenuUsecase) GetAll() ([]domain.Menu, error) {
 	return menus, nil
 }
 
+// ExportMenus はすべてのメニューを一定件数ずつ取得し、fnに渡す
+func (u MenuUsecase) ExportMenus(fn func(menus []domain.Menu) error) error {
+	return u.menuPort.EachMenuBatch(fn)
+}
+
+func (u MenuUsecase) FindMenus(query domain.MenuQuery) (domain.MenuPage, error) {
+	// ログインユーザーの食事制限プロファイルに反するメニューを除外する
+	if query.UserId != 0 {
+		profile, err := u.profilePort.GetDietaryProfile(query.UserId)
+		if err != nil {
+			return domain.MenuPage{}, err
+		}
+		query.ExcludeAllergens = profile.Allergens
+		query.RequireDiets = profile.Diets
+	}
+
+	page, err := u.menuPort.FindMenus(query)
+
+	if err != nil {
+		return domain.MenuPage{}, err
+	}
+
+	return page, nil
+}
+
+func (u MenuUsecase) GetMenu(menuId uint) (domain.Menu, error) {
+	menu, err := u.menuPort.GetMenu(menuId)
+
+	if err != nil {
+		return domain.Menu{}, err
+	}
+
+	return menu, nil
+}
+
 func (u MenuUsecase) CreateMenu(menu domain.Menu) (domain.Menu, error) {
+	if err := u.validateMenu(menu); err != nil {
+		return domain.Menu{}, err
+	}
+
 	menus, err := u.menuPort.CreateMenu(menu)
 	if err != nil {
 		return domain.Menu{}, err
@@ -33,6 +77,10 @@ func (u MenuUsecase) CreateMenu(menu domain.Menu) (domain.Menu, error) {
 }
 
 func (u MenuUsecase) UpdateMenu(menu domain.Menu) (domain.Menu, error) {
+	if err := u.validateMenu(menu); err != nil {
+		return domain.Menu{}, err
+	}
+
 	menu, err := u.menuPort.UpdateMenu(menu)
 
 	if err != nil {
@@ -43,6 +91,14 @@ func (u MenuUsecase) UpdateMenu(menu domain.Menu) (domain.Menu, error) {
 }
 
 func (u MenuUsecase) UpdateGenreRelations(menuId uint, genreIds []uint) (domain.Menu, error) {
+	fields, err := u.validateGenreIds(genreIds)
+	if err != nil {
+		return domain.Menu{}, err
+	}
+	if err := toValidationError(fields); err != nil {
+		return domain.Menu{}, err
+	}
+
 	menu, err := u.menuPort.UpdateGenreRelations(menuId, genreIds)
 
 	if err != nil {
@@ -53,6 +109,14 @@ func (u MenuUsecase) UpdateGenreRelations(menuId uint, genreIds []uint) (domain.
 }
 
 func (u MenuUsecase) UpdateCategoryRelations(menuId uint, categoryIds []uint) (domain.Menu, error) {
+	fields, err := u.validateCategoryIds(categoryIds)
+	if err != nil {
+		return domain.Menu{}, err
+	}
+	if err := toValidationError(fields); err != nil {
+		return domain.Menu{}, err
+	}
+
 	menu, err := u.menuPort.UpdateCategoryRelations(menuId, categoryIds)
 
 	if err != nil {
@@ -62,6 +126,65 @@ func (u MenuUsecase) UpdateCategoryRelations(menuId uint, categoryIds []uint) (d
 	return menu, nil
 }
 
+func (u MenuUsecase) UpdateAllergens(menuId uint, allergens []string) (domain.Menu, error) {
+	if err := toValidationError(validateTags("allergens", allergens, domain.Allergens)); err != nil {
+		return domain.Menu{}, err
+	}
+
+	menu, err := u.menuPort.UpdateAllergens(menuId, allergens)
+
+	if err != nil {
+		return domain.Menu{}, err
+	}
+
+	return menu, nil
+}
+
+func (u MenuUsecase) UpdateDiets(menuId uint, diets []string) (domain.Menu, error) {
+	if err := toValidationError(validateTags("diets", diets, domain.Diets)); err != nil {
+		return domain.Menu{}, err
+	}
+
+	menu, err := u.menuPort.UpdateDiets(menuId, diets)
+
+	if err != nil {
+		return domain.Menu{}, err
+	}
+
+	return menu, nil
+}
+
+func (u MenuUsecase) GetRecipe(menuId uint) (domain.Recipe, error) {
+	recipe, err := u.menuPort.GetRecipe(menuId)
+
+	if err != nil {
+		return domain.Recipe{}, err
+	}
+
+	return recipe, nil
+}
+
+func (u MenuUsecase) UpdateRecipe(recipe domain.Recipe) (domain.Recipe, error) {
+	// 売り場の指定がない材料はその他として扱う
+	for i := range recipe.Ingredients {
+		if recipe.Ingredients[i].Section == "" {
+			recipe.Ingredients[i].Section = domain.SectionOther
+		}
+	}
+
+	if err := validateRecipe(recipe); err != nil {
+		return domain.Recipe{}, err
+	}
+
+	recipe, err := u.menuPort.UpdateRecipe(recipe)
+
+	if err != nil {
+		return domain.Recipe{}, err
+	}
+
+	return recipe, nil
+}
+
 func (u MenuUsecase) DeleteMenu(menuId uint) error {
 	err := u.menuPort.DeleteMenu(menuId)
 
@@ -71,3 +194,18 @@ func (u MenuUsecase) DeleteMenu(menuId uint) error {
 
 	return nil
 }
+
+func (u MenuUsecase) RestoreMenu(menuId uint) (domain.Menu, error) {
+	menu, err := u.menuPort.RestoreMenu(menuId)
+
+	if err != nil {
+		return domain.Menu{}, err
+	}
+
+	return menu, nil
+}
+
+// PurgeDeletedMenus は論理削除してから保持期間が経過したメニューを物理削除する
+func (u MenuUsecase) PurgeDeletedMenus(retention time.Duration) (int64, error) {
+	return u.menuPort.PurgeDeletedMenus(time.Now().Add(-retention))
+}